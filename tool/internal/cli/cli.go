@@ -1,4 +1,4 @@
-// CRC: crc-CLI.md | R79, R80, R81, R82, R83
+// CRC: crc-CLI.md | R79, R80, R81, R82, R83, R90, R93, R97, R103, R116, R120, R123, R125, R126, R127, R128, R129, R130, R131, R132, R133, R134, R135, R136, R137, R138
 package cli
 
 import (
@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/zot/minispec/internal/export"
+	"github.com/zot/minispec/internal/parser"
 	"github.com/zot/minispec/internal/phase"
 	"github.com/zot/minispec/internal/project"
 	"github.com/zot/minispec/internal/query"
@@ -47,8 +49,7 @@ func (c *CLI) Run(args []string) int {
 	fs := flag.NewFlagSet("minispec", flag.ContinueOnError)
 	fs.StringVar(&c.DesignDir, "design-dir", "", "Override design directory")
 	fs.StringVar(&c.SrcDir, "src-dir", "", "Override source directory")
-	fs.BoolVar(&c.Quiet, "quiet", false, "Minimal output")
-	fs.BoolVar(&c.JSON, "json", false, "Output as JSON")
+	c.outputFlags(fs)
 
 	// Find command position (first non-flag arg)
 	cmdIdx := 0
@@ -77,6 +78,8 @@ func (c *CLI) Run(args []string) int {
 		return c.runQuery(cmdArgs)
 	case "update":
 		return c.runUpdate(cmdArgs)
+	case "export":
+		return c.runExport(cmdArgs)
 	case "validate":
 		return c.runValidate(cmdArgs)
 	case "phase":
@@ -100,11 +103,19 @@ Commands:
   check-version         Verify tool and skill versions match
   query <subcommand>    Query design files
   update <subcommand>   Update design files
+  export <subcommand>   Export the traceability data for other tools
   validate              Run structural validations
+                          --fix: apply automatic fixes, then re-validate
+                          --trust design|code: side kept when fixing trace mismatches
+                          --list-rules: list rule IDs, severities and on/off state
+                          --fail-on error|warning|info: least severity that fails (default error)
+                          --min-design-coverage N, --min-impl-coverage N: fail below N percent
   phase <phase-name>    Run phase-specific validation
+                          --fail-on error|warning|info: least severity that fails (default error)
 
-Query subcommands:
-  requirements          List all requirements
+Query subcommands (those listing records accept --where EXPR, e.g. 'retired == false && tags contains "ui"'):
+  requirements          List all requirements, grouped by feature
+                          --tag T, --priority P, --status S: filter by metadata
   coverage              Show requirement coverage by design files
   uncovered             List requirements with no design coverage
   orphan-designs        List CRC cards missing Requirements field
@@ -114,6 +125,16 @@ Query subcommands:
   traceability <file>   Check file for traceability comments
   traceability --all    Check all code files
   comment-patterns      Show recognized comment patterns per file extension
+  trace <Rn>            Show a requirement's spec source, design files, code comments, gaps and artifacts
+  impact <path|Rn>      List the specs, requirements, design and code files a change may affect
+  search <regex>        Search requirement text, CRC items, sequence messages, gaps and spec headings
+                          --in requirements,crc,seq,gaps,specs: only these scopes
+  requirement <Rn>      Show a requirement; for a retired one, follow its replacements to the current one
+  features              List features with source spec, requirements and coverage
+  stats                 Show requirement, coverage, artifact and gap counts per feature and spec
+  crc <name|file>       Show a CRC card's fields and sections with line ranges
+  sequence <file>       Show participants, messages and blocks of a seq-*.md diagram
+  collaborators [card]  Show the CRC collaboration graph (one-way edges marked)
 
 Update subcommands:
   check <file> <item>           Check a checkbox
@@ -124,8 +145,20 @@ Update subcommands:
   resolve-gap <id>              Mark gap as resolved (S/R/D/C/I/O only)
   approve-gap <id>              Convert gap to approved (A) type
   retire <Rold> <Rnew|-> <reason>  Retire a requirement, append a Tn gap
+  acknowledge <crc>             Record the card as reviewed against its requirements' current text
+  acknowledge-spec <path>       Record a spec's current fingerprint on the Source lines citing it
   migration-complete <name>     Move migration spec to complete/ with NNN- prefix
 
+Export subcommands:
+  graph                 Write the traceability graph
+                          --format dot|mermaid|graphml (default dot)
+                          --focus Rn|file: only nodes near a requirement or file
+                          --depth N: links to follow from the focus (default 2)
+  matrix                Write one row per requirement: spec, design, code, tests, artifacts, gaps
+                          --format md|csv|json (default md)
+                          --retired: include retired requirements
+                          --feature NAME: only one feature's requirements
+
 Phase subcommands:
   spec                  Validate spec files exist
   requirements          Validate requirements.md format
@@ -189,6 +222,13 @@ func readSkillVersion(path string) (string, error) {
 	return "", fmt.Errorf("no Version line found")
 }
 
+// outputFlags defines --quiet and --json on fs. Commands that parse their
+// own flags define them too, so that they may also follow the command.
+func (c *CLI) outputFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&c.Quiet, "quiet", c.Quiet, "Minimal output")
+	fs.BoolVar(&c.JSON, "json", c.JSON, "Output as JSON")
+}
+
 func (c *CLI) getProject() (*project.Project, error) {
 	p, err := project.Detect()
 	if err != nil {
@@ -216,7 +256,79 @@ func (c *CLI) output(data any) {
 	}
 }
 
+// splitWhere removes a --where <expr> (or --where=<expr>) flag from query
+// arguments and parses the expression; where is nil when none is given. R133
+func splitWhere(args []string) (rest []string, where *query.Where, err error) {
+	expr, found := "", false
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--where" || arg == "-where":
+			if i+1 == len(args) {
+				return nil, nil, fmt.Errorf("--where needs an expression")
+			}
+			expr, found = args[i+1], true
+			i++
+		case strings.HasPrefix(arg, "--where=") || strings.HasPrefix(arg, "-where="):
+			_, expr, _ = strings.Cut(arg, "=")
+			found = true
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	if !found {
+		return rest, nil, nil
+	}
+	where, err = query.ParseWhere(expr)
+	return rest, where, err
+}
+
+// listRecords filters a query's records with --where and prints them, as
+// JSON or with print. Every query that lists records goes through it, so
+// each one accepts --where. R133
+func listRecords[T any](c *CLI, where *query.Where, records []T, err error, print func([]T)) int {
+	if err == nil {
+		records, err = query.Filter(where, records)
+	}
+	return showResult(c, nil, records, err, print)
+}
+
+// listValues is listRecords for plain values (Rn IDs, paths), each seen by
+// --where as a record with the single field name. R133
+func listValues(c *CLI, where *query.Where, name string, values []string, err error) int {
+	if err == nil {
+		values, err = query.FilterStrings(where, values, name)
+	}
+	return showResult(c, nil, values, err, func(values []string) {
+		for _, v := range values {
+			fmt.Println(v)
+		}
+	})
+}
+
+// showResult prints a query result, as JSON or with print. The result is not
+// a list of records, so --where is rejected. R133
+func showResult[T any](c *CLI, where *query.Where, res T, err error, print func(T)) int {
+	if err == nil && where != nil {
+		err = fmt.Errorf("this query does not list records; --where is not supported")
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if c.JSON {
+		c.output(res)
+	} else {
+		print(res)
+	}
+	return 0
+}
+
 func (c *CLI) runQuery(args []string) int {
+	args, where, err := splitWhere(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
 	if len(args) < 1 {
 		fmt.Fprintln(os.Stderr, "Usage: minispec query <subcommand>")
 		return 1
@@ -233,99 +345,103 @@ func (c *CLI) runQuery(args []string) int {
 
 	switch subcmd {
 	case "requirements":
-		reqs, err := q.Requirements()
-		if err != nil {
+		fs := flag.NewFlagSet("query requirements", flag.ContinueOnError)
+		c.outputFlags(fs)
+		var filter query.RequirementFilter
+		fs.StringVar(&filter.Tag, "tag", "", "Only requirements with this #tag")
+		fs.StringVar(&filter.Priority, "priority", "", "Only requirements with this priority")
+		fs.StringVar(&filter.Status, "status", "", "Only requirements with this status (open matches none given)")
+		if err := fs.Parse(args[1:]); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			return 1
 		}
-		if c.JSON {
-			c.output(reqs)
-		} else {
-			for _, r := range reqs {
-				inferred := ""
+		reqs, err := q.FilterRequirements(filter)
+		return listRecords(c, where, reqs, err, func(reqs []parser.Requirement) {
+			feature := ""
+			for i, r := range reqs {
+				if i == 0 || r.Feature != feature {
+					feature = r.Feature
+					if i > 0 {
+						fmt.Println()
+					}
+					if feature != "" {
+						fmt.Printf("Feature: %s\n", feature)
+					}
+				}
+				suffix := ""
 				if r.Inferred {
-					inferred = " (inferred)"
+					suffix = " (inferred)"
+				}
+				var meta []string
+				if r.Priority != "" {
+					meta = append(meta, r.Priority)
+				}
+				if r.Status != "" {
+					meta = append(meta, r.Status)
+				}
+				for _, tag := range r.Tags {
+					meta = append(meta, "#"+tag)
+				}
+				if len(meta) > 0 {
+					suffix += " [" + strings.Join(meta, " ") + "]"
+				}
+				fmt.Printf("%s: %s%s\n", r.ID, r.Text, suffix)
+				if r.SourceAnchor() != "" {
+					if h, ok, _ := q.SpecSection(r.Source); ok {
+						fmt.Printf("  source: %s (line %d: %s)\n", r.Source, h.Line, h.Title)
+					} else {
+						fmt.Printf("  source: %s (heading not found)\n", r.Source)
+					}
 				}
-				fmt.Printf("%s: %s%s\n", r.ID, r.Text, inferred)
 			}
-		}
+		})
 
-	case "coverage":
-		cov, err := q.Coverage()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	case "requirement":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "Usage: minispec query requirement <Rn>")
 			return 1
 		}
-		if c.JSON {
-			c.output(cov)
-		} else {
-			for id, files := range cov.Coverage {
-				if len(files) > 0 {
-					fmt.Printf("%s: %s\n", id, strings.Join(files, ", "))
+		res, err := q.Requirement(args[1])
+		return showResult(c, where, res, err, printRequirement)
+
+	case "coverage":
+		cov, err := q.CoverageList()
+		return listRecords(c, where, cov, err, func(cov []query.RequirementCoverage) {
+			for _, rc := range cov {
+				if len(rc.Files) > 0 {
+					fmt.Printf("%s: %s\n", rc.ID, strings.Join(rc.Files, ", "))
 				} else {
-					fmt.Printf("%s: (none)\n", id)
+					fmt.Printf("%s: (none)\n", rc.ID)
 				}
 			}
-		}
+		})
 
 	case "uncovered":
 		uncovered, err := q.Uncovered()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			return 1
-		}
-		if c.JSON {
-			c.output(uncovered)
-		} else {
-			for _, id := range uncovered {
-				fmt.Println(id)
-			}
-		}
+		return listValues(c, where, "id", uncovered, err)
 
 	case "orphan-designs":
 		orphans, err := q.OrphanDesigns()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			return 1
-		}
-		if c.JSON {
-			c.output(orphans)
-		} else {
-			for _, path := range orphans {
-				fmt.Println(path)
-			}
-		}
+		return listValues(c, where, "path", orphans, err)
 
 	case "artifacts":
-		artifacts, err := q.Artifacts()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			return 1
-		}
-		if c.JSON {
-			c.output(artifacts)
-		} else {
-			for _, art := range artifacts {
-				fmt.Println(art.DesignFile)
-				for _, cf := range art.CodeFiles {
-					mark := " "
-					if cf.Checked {
-						mark = "x"
-					}
-					fmt.Printf("  [%s] %s\n", mark, cf.Path)
+		files, err := q.ArtifactFiles()
+		return listRecords(c, where, files, err, func(files []query.ArtifactFile) {
+			for i, f := range files {
+				if i == 0 || f.DesignFile != files[i-1].DesignFile {
+					fmt.Println(f.DesignFile)
+				}
+				mark := " "
+				if f.Checked {
+					mark = "x"
 				}
+				fmt.Printf("  [%s] %s\n", mark, f.Path)
 			}
-		}
+		})
 
 	case "gaps":
 		gaps, err := q.Gaps()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			return 1
-		}
-		if c.JSON {
-			c.output(gaps)
-		} else {
+		return listRecords(c, where, gaps, err, func(gaps []parser.Gap) {
 			for _, g := range gaps {
 				if !g.HasCheckbox {
 					fmt.Printf("    %s: %s\n", g.ID, g.Description)
@@ -337,21 +453,11 @@ func (c *CLI) runQuery(args []string) int {
 				}
 				fmt.Printf("[%s] %s: %s\n", mark, g.ID, g.Description)
 			}
-		}
+		})
 
 	case "migrations":
 		migs, err := q.Migrations()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			return 1
-		}
-		if c.JSON {
-			c.output(migs)
-		} else {
-			for _, m := range migs {
-				fmt.Println(m)
-			}
-		}
+		return listValues(c, where, "path", migs, err)
 
 	case "traceability":
 		if len(args) < 2 {
@@ -359,56 +465,163 @@ func (c *CLI) runQuery(args []string) int {
 			return 1
 		}
 		if args[1] == "--all" {
-			traces, err := q.TraceabilityAll()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				return 1
-			}
-			if c.JSON {
-				c.output(traces)
-			} else {
-				for path, trace := range traces {
-					if len(trace.CRCRefs) > 0 {
-						fmt.Printf("%s: CRC=%s", path, strings.Join(trace.CRCRefs, ","))
-						if len(trace.SeqRefs) > 0 {
-							fmt.Printf(" Seq=%s", strings.Join(trace.SeqRefs, ","))
+			traces, err := q.TraceabilityList()
+			return listRecords(c, where, traces, err, func(traces []query.FileTraceability) {
+				for _, t := range traces {
+					if len(t.CRCRefs) > 0 {
+						fmt.Printf("%s: CRC=%s", t.Path, strings.Join(t.CRCRefs, ","))
+						if len(t.SeqRefs) > 0 {
+							fmt.Printf(" Seq=%s", strings.Join(t.SeqRefs, ","))
 						}
 						fmt.Println()
 					} else {
-						fmt.Printf("%s: (missing)\n", path)
+						fmt.Printf("%s: (missing)\n", t.Path)
 					}
 				}
+			})
+		}
+		trace, err := q.Traceability(args[1])
+		return showResult(c, where, trace, err, func(trace parser.Traceability) {
+			if len(trace.CRCRefs) > 0 {
+				fmt.Printf("CRC: %s\n", strings.Join(trace.CRCRefs, ", "))
 			}
-		} else {
-			trace, err := q.Traceability(args[1])
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				return 1
+			if len(trace.SeqRefs) > 0 {
+				fmt.Printf("Seq: %s\n", strings.Join(trace.SeqRefs, ", "))
 			}
-			if c.JSON {
-				c.output(trace)
-			} else {
-				if len(trace.CRCRefs) > 0 {
-					fmt.Printf("CRC: %s\n", strings.Join(trace.CRCRefs, ", "))
+			if len(trace.CRCRefs) == 0 && len(trace.SeqRefs) == 0 {
+				fmt.Println("(no traceability comments found)")
+			}
+		})
+
+	case "trace":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "Usage: minispec query trace <Rn>")
+			return 1
+		}
+		trace, err := q.Trace(args[1])
+		return showResult(c, where, trace, err, printTrace)
+
+	case "impact":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "Usage: minispec query impact <path|Rn>")
+			return 1
+		}
+		impact, err := q.Impact(args[1])
+		return showResult(c, where, impact, err, func(impact *query.ImpactResult) {
+			fmt.Printf("specs: %s\n", strings.Join(impact.Specs, ", "))
+			fmt.Printf("requirements: %s\n", validate.FormatRanges(impact.Requirements))
+			fmt.Printf("design: %s\n", strings.Join(impact.DesignFiles, ", "))
+			fmt.Printf("code: %s\n", strings.Join(impact.CodeFiles, ", "))
+		})
+
+	case "search":
+		fs := flag.NewFlagSet("query search", flag.ContinueOnError)
+		c.outputFlags(fs)
+		in := fs.String("in", "", "Comma-separated scopes ("+strings.Join(query.SearchScopes, ",")+")")
+		if err := fs.Parse(args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if fs.NArg() < 1 {
+			fmt.Fprintln(os.Stderr, "Usage: minispec query search <regex> [--in requirements,crc,seq,gaps,specs]")
+			return 1
+		}
+		pattern := fs.Arg(0)
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		var scopes []string
+		if *in != "" {
+			scopes = strings.Split(*in, ",")
+		}
+		hits, err := q.Search(pattern, scopes)
+		return listRecords(c, where, hits, err, func(hits []query.SearchHit) {
+			for _, h := range hits {
+				fmt.Printf("%s:%d: %s %s %s: %s\n", h.File, h.Line, h.Kind, h.ID, h.Field, h.Text)
+			}
+		})
+
+	case "features":
+		features, err := q.Features()
+		return listRecords(c, where, features, err, func(features []query.FeatureSummary) {
+			for _, f := range features {
+				name := f.Name
+				if name == "" {
+					name = "(no feature)"
 				}
-				if len(trace.SeqRefs) > 0 {
-					fmt.Printf("Seq: %s\n", strings.Join(trace.SeqRefs, ", "))
+				source := f.Source
+				if source == "" {
+					source = "no source"
 				}
-				if len(trace.CRCRefs) == 0 && len(trace.SeqRefs) == 0 {
-					fmt.Println("(no traceability comments found)")
+				fmt.Printf("%s (%s): %s\n", name, source, validate.FormatRanges(f.Requirements))
+				fmt.Printf("  design %s, implementation %s\n", ratio(f.Stats.DesignCovered, f.Stats.Tracked), ratio(f.Stats.ImplCovered, f.Stats.Tracked))
+			}
+		})
+
+	case "stats":
+		stats, err := q.Stats()
+		return showResult(c, where, stats, err, printStats)
+
+	case "crc":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "Usage: minispec query crc <name|file>")
+			return 1
+		}
+		card, err := q.CRC(args[1])
+		return showResult(c, where, card, err, printCRCCard)
+
+	case "sequence":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "Usage: minispec query sequence <file>")
+			return 1
+		}
+		seq, err := q.Sequence(args[1])
+		return showResult(c, where, seq, err, func(seq parser.Sequence) {
+			fmt.Printf("participants: %s\n", strings.Join(seq.Participants, ", "))
+			fmt.Println("messages:")
+			for _, m := range seq.Messages {
+				arrow := "->"
+				if m.Reply {
+					arrow = "-->"
 				}
+				fmt.Printf("  %d: %s %s %s: %s\n", m.Line, m.From, arrow, m.To, m.Text)
 			}
+			if len(seq.Blocks) > 0 {
+				fmt.Println("blocks:")
+				for _, b := range seq.Blocks {
+					fmt.Printf("  %d-%d: %s %s\n", b.Line, b.EndLine, b.Kind, b.Label)
+				}
+			}
+		})
+
+	case "collaborators":
+		card := ""
+		if len(args) > 1 {
+			card = args[1]
 		}
+		edges, err := q.Collaborators(card)
+		return listRecords(c, where, edges, err, func(edges []query.CollaboratorEdge) {
+			for _, e := range edges {
+				line := e.From + " -> " + e.To
+				if e.Note != "" {
+					line += " (" + e.Note + ")"
+				}
+				if !e.Mutual && !e.NoCard {
+					line += " [one-way]"
+				}
+				fmt.Println(line)
+			}
+		})
 
 	case "comment-patterns":
 		patterns := q.CommentPatterns()
 		closers := q.CommentClosers()
-		if c.JSON {
-			c.output(map[string]any{
-				"patterns": patterns,
-				"closers":  closers,
-			})
-		} else {
+		res := map[string]any{
+			"patterns": patterns,
+			"closers":  closers,
+		}
+		return showResult(c, where, res, nil, func(map[string]any) {
 			fmt.Println("Recognized comment patterns:")
 			for ext, pattern := range patterns {
 				fmt.Printf("  %s: %s\n", ext, pattern)
@@ -423,14 +636,195 @@ func (c *CLI) runQuery(args []string) int {
 				fmt.Println("WARNING: Extensions with closers use block comments.")
 				fmt.Println("An unclosed comment will silently swallow all subsequent code.")
 			}
-		}
+		})
 
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown query subcommand: %s\n", subcmd)
 		return 1
 	}
+}
 
-	return 0
+// printTrace prints the text form of `query trace`. R127
+func printTrace(t *query.TraceResult) {
+	r := t.Requirement
+	state := r.StatusOrOpen()
+	if r.Retired {
+		state = "retired"
+	}
+	fmt.Printf("%s: %s\n", r.ID, r.Text)
+	fmt.Printf("status: %s\n", state)
+	if t.Section != nil {
+		fmt.Printf("source: %s (line %d: %s)\n", r.Source, t.Section.Line, t.Section.Title)
+	} else if r.Source != "" {
+		fmt.Printf("source: %s\n", r.Source)
+	}
+	fmt.Printf("crc: %s\n", strings.Join(t.CRCCards, ", "))
+	fmt.Printf("sequences: %s\n", strings.Join(t.Sequences, ", "))
+	if len(t.Code) > 0 {
+		fmt.Println("code:")
+		for _, ref := range t.Code {
+			fmt.Printf("  %s:%d\n", ref.File, ref.Line)
+		}
+	}
+	if len(t.Gaps) > 0 {
+		fmt.Println("gaps:")
+		for _, g := range t.Gaps {
+			fmt.Printf("  %s: %s\n", g.ID, g.Description)
+		}
+	}
+	if len(t.Artifacts) > 0 {
+		fmt.Println("artifacts:")
+		for _, a := range t.Artifacts {
+			var files []string
+			for _, cf := range a.CodeFiles {
+				mark := " "
+				if cf.Checked {
+					mark = "x"
+				}
+				files = append(files, fmt.Sprintf("[%s] %s", mark, cf.Path))
+			}
+			fmt.Printf("  %s → %s\n", a.DesignFile, strings.Join(files, ", "))
+		}
+	}
+}
+
+// printCRCCard prints the text form of `query crc`. R116
+func printCRCCard(card parser.CRCCard) {
+	fmt.Printf("name: %s\n", card.Name)
+	fmt.Printf("file: %s\n", filepath.Base(card.Path))
+	if card.Type != "" {
+		fmt.Printf("type: %s\n", card.Type)
+	}
+	if len(card.Requirements) > 0 {
+		fmt.Printf("requirements: %s\n", strings.Join(card.Requirements, ", "))
+	}
+	for _, list := range []struct {
+		label string
+		items []string
+	}{
+		{"responsibilities", card.Responsibilities},
+		{"knows", card.Knows},
+		{"does", card.Does},
+	} {
+		if len(list.items) == 0 {
+			continue
+		}
+		fmt.Printf("%s:\n", list.label)
+		for _, item := range list.items {
+			fmt.Printf("  - %s\n", item)
+		}
+	}
+	if len(card.Collaborators) > 0 {
+		fmt.Println("collaborators:")
+		for _, col := range card.Collaborators {
+			if col.Note != "" {
+				fmt.Printf("  - %s (%s)\n", col.Name, col.Note)
+			} else {
+				fmt.Printf("  - %s\n", col.Name)
+			}
+		}
+	}
+	if len(card.Interface) > 0 {
+		fmt.Println("interface:")
+		for _, sig := range card.Interface {
+			maxArgs := "*"
+			if sig.MaxArgs >= 0 {
+				maxArgs = fmt.Sprint(sig.MaxArgs)
+			}
+			fmt.Printf("  %d: %s (%d..%s args)\n", sig.Line, sig.Name, sig.MinArgs, maxArgs)
+		}
+	}
+	if len(card.Sequences) > 0 {
+		fmt.Printf("sequences: %s\n", strings.Join(card.Sequences, ", "))
+	}
+	if len(card.Sections) > 0 {
+		fmt.Println("sections:")
+		for _, s := range card.Sections {
+			fmt.Printf("  %d-%d: %s\n", s.Line, s.EndLine, s.Title)
+		}
+	}
+}
+
+// printRequirement prints a requirement and, when it is retired, each step
+// of its retirement chain and where the chain ends. R135
+func printRequirement(res *query.RequirementResult) {
+	r := res.Requirement
+	fmt.Printf("%s: %s\n", r.ID, r.Text)
+	if r.Feature != "" {
+		fmt.Printf("  feature: %s\n", r.Feature)
+	}
+	if r.Source != "" {
+		fmt.Printf("  source: %s\n", r.Source)
+	}
+	if !r.Retired {
+		fmt.Printf("  status: %s\n", r.StatusOrOpen())
+		return
+	}
+	fmt.Println("  status: retired")
+	for _, step := range res.Chain {
+		to := step.ReplacedBy
+		if to == "" {
+			to = "(no replacement)"
+		}
+		gap := step.Gap
+		if gap == "" {
+			gap = "no T gap"
+		}
+		if step.Reason != "" {
+			gap += ": " + step.Reason
+		}
+		fmt.Printf("  %s -> %s (%s)\n", step.Requirement, to, gap)
+	}
+	switch {
+	case len(res.Cycle) > 0:
+		fmt.Printf("  cycle: %s\n", strings.Join(res.Cycle, " -> "))
+	case res.DeadEnd != "":
+		fmt.Printf("  dead end: %s\n", res.DeadEnd)
+	default:
+		fmt.Printf("  current: %s\n", res.Current)
+	}
+}
+
+// ratio formats n out of total with its percentage.
+func ratio(n, total int) string {
+	if total == 0 {
+		return "0/0"
+	}
+	return fmt.Sprintf("%d/%d (%.0f%%)", n, total, query.Percent(n, total))
+}
+
+// printStats prints project totals, then one line per feature and per
+// source spec. R131
+func printStats(s *query.StatsResult) {
+	counts := func(r query.RequirementStats) string {
+		return fmt.Sprintf("%d active, %d retired, %d inferred, %d deferred", r.Active, r.Retired, r.Inferred, r.Deferred)
+	}
+	line := func(r query.RequirementStats) string {
+		return fmt.Sprintf("%s; design %s, implementation %s", counts(r), ratio(r.DesignCovered, r.Tracked), ratio(r.ImplCovered, r.Tracked))
+	}
+
+	fmt.Printf("requirements: %d (%s)\n", s.Requirements.Total, counts(s.Requirements))
+	fmt.Printf("design coverage: %s\n", ratio(s.Requirements.DesignCovered, s.Requirements.Tracked))
+	fmt.Printf("implementation coverage: %s\n", ratio(s.Requirements.ImplCovered, s.Requirements.Tracked))
+	fmt.Printf("artifacts checked: %s\n", ratio(s.Artifacts.Checked, s.Artifacts.Total))
+	fmt.Printf("gaps: %d open, %d resolved, %d approved\n", s.Gaps.Open, s.Gaps.Resolved, s.Gaps.Approved)
+	for _, group := range []struct {
+		title string
+		stats []query.RequirementStats
+		none  string
+	}{
+		{"features", s.Features, "(no feature)"},
+		{"specs", s.Specs, "(no source)"},
+	} {
+		fmt.Printf("\n%s:\n", group.title)
+		for _, r := range group.stats {
+			name := r.Name
+			if name == "" {
+				name = group.none
+			}
+			fmt.Printf("  %s: %s\n", name, line(r))
+		}
+	}
 }
 
 func (c *CLI) runUpdate(args []string) int {
@@ -561,6 +955,34 @@ func (c *CLI) runUpdate(args []string) int {
 		}
 		fmt.Println(tn)
 
+	case "acknowledge":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "Usage: minispec update acknowledge <crc-file>")
+			return 1
+		}
+		ids, err := u.Acknowledge(args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if !c.Quiet {
+			fmt.Printf("Acknowledged %s against %s\n", args[1], validate.FormatRanges(ids))
+		}
+
+	case "acknowledge-spec":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "Usage: minispec update acknowledge-spec <spec-path[#anchor]>")
+			return 1
+		}
+		n, err := u.AcknowledgeSpec(args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if !c.Quiet {
+			fmt.Printf("Acknowledged %s on %d Source line(s)\n", args[1], n)
+		}
+
 	case "migration-complete":
 		if len(args) < 2 {
 			fmt.Fprintln(os.Stderr, "Usage: minispec update migration-complete <name>")
@@ -581,12 +1003,112 @@ func (c *CLI) runUpdate(args []string) int {
 	return 0
 }
 
-func (c *CLI) runValidate(_ []string) int {
+func (c *CLI) runExport(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: minispec export <subcommand>")
+		return 1
+	}
+
+	p, err := c.getProject()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	q := query.New(p)
+	subcmd := args[0]
+
+	switch subcmd {
+	case "graph":
+		fs := flag.NewFlagSet("export graph", flag.ContinueOnError)
+		c.outputFlags(fs)
+		format := fs.String("format", "dot", "Output format ("+strings.Join(export.Formats, "|")+")")
+		focus := fs.String("focus", "", "Only nodes near this requirement or file")
+		depth := fs.Int("depth", 2, "Links to follow from the focus")
+		if err := fs.Parse(args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		g, err := q.Graph()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if *focus != "" {
+			if g, err = g.Focus(q.NodeID(*focus), *depth); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+		}
+		if c.JSON {
+			c.output(g)
+		} else if err := export.Graph(os.Stdout, g, *format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+
+	case "matrix":
+		fs := flag.NewFlagSet("export matrix", flag.ContinueOnError)
+		c.outputFlags(fs)
+		format := fs.String("format", "md", "Output format ("+strings.Join(export.MatrixFormats, "|")+")")
+		var opts query.MatrixOptions
+		fs.BoolVar(&opts.Retired, "retired", false, "Include retired requirements")
+		fs.StringVar(&opts.Feature, "feature", "", "Only requirements of this feature")
+		if err := fs.Parse(args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		rows, err := q.Matrix(opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if c.JSON {
+			c.output(rows)
+		} else if err := export.Matrix(os.Stdout, rows, *format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown export subcommand: %s\n", subcmd)
+		return 1
+	}
+
+	return 0
+}
+
+func (c *CLI) runValidate(args []string) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	c.outputFlags(fs)
+	fix := fs.Bool("fix", false, "Apply automatic fixes, then re-validate")
+	trust := fs.String("trust", "design", "Side taken as correct when fixing artifact trace mismatches (design|code)")
+	listRules := fs.Bool("list-rules", false, "List the validation rules instead of validating")
+	failOn := fs.String("fail-on", "error", "Least severity that fails validation (error|warning|info)")
+	minDesign := fs.Float64("min-design-coverage", 0, "Fail when design coverage is below this percentage")
+	minImpl := fs.Float64("min-impl-coverage", 0, "Fail when implementation coverage is below this percentage")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if *trust != "design" && *trust != "code" {
+		fmt.Fprintln(os.Stderr, "--trust must be design or code")
+		return 1
+	}
+	failSeverity, err := validate.ParseSeverity(*failOn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --fail-on: %v\n", err)
+		return 1
+	}
+
 	p, err := c.getProject()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return 1
 	}
+	if *listRules {
+		return c.printRules(p)
+	}
 
 	v := validate.New(p)
 	result, err := v.Run()
@@ -595,18 +1117,86 @@ func (c *CLI) runValidate(_ []string) int {
 		return 1
 	}
 
+	if *fix && c.applyFixes(update.New(p), result, *trust) > 0 {
+		if result, err = v.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	result.SetThresholds(validate.Thresholds{FailOn: failSeverity, MinDesignCoverage: *minDesign, MinImplCoverage: *minImpl})
+
 	if c.JSON {
 		c.output(result)
 	} else {
 		fmt.Print(result.FormatText())
 	}
 
-	if result.HasIssues() {
+	if !result.Passed {
 		return 1
 	}
 	return 0
 }
 
+// printRules lists the validation rules in report order with their severity
+// and whether .minispec.yaml leaves them on. R137, R138
+func (c *CLI) printRules(p *project.Project) int {
+	reg := validate.DefaultRegistry()
+	if err := reg.Configure(p.Config); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: .minispec.yaml: %v\n", err)
+		return 1
+	}
+	type ruleInfo struct {
+		ID          string
+		Severity    validate.Severity
+		Enabled     bool
+		Description string
+	}
+	var rules []ruleInfo
+	for _, rule := range reg.Rules() {
+		rules = append(rules, ruleInfo{rule.ID(), reg.Severity(rule.ID()), reg.Enabled(rule.ID()), rule.Description()})
+	}
+	if c.JSON {
+		c.output(rules)
+		return 0
+	}
+	for _, r := range rules {
+		state := "on"
+		if !r.Enabled {
+			state = "off"
+		}
+		fmt.Printf("%-36s %-7s %-3s %s\n", r.ID, r.Severity, state, r.Description)
+	}
+	return 0
+}
+
+// applyFixes repairs the fixable issues in result and returns how many fixes
+// were written. Failures are reported and left for the re-validation. R90
+func (c *CLI) applyFixes(u *update.Update, result *validate.ValidationResult, trust string) int {
+	fixed := 0
+	report := func(err error, format string, args ...any) {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "fix failed: %s: %v\n", fmt.Sprintf(format, args...), err)
+			return
+		}
+		fixed++
+		if !c.Quiet && !c.JSON {
+			fmt.Printf("fixed: %s\n", fmt.Sprintf(format, args...))
+		}
+	}
+	for _, f := range result.Findings["unclosed-trace-comments"] {
+		report(u.CloseTraceComment(f.File, f.Line), "%s closed", f.Item)
+	}
+	for _, m := range result.TraceMismatches() {
+		err := u.FixTraceMismatch(m.CodeFile, m.DesignFile, m.MissingFrom, trust)
+		report(err, "%s → %s (trusting %s)", m.CodeFile, m.DesignFile, trust)
+	}
+	if fixed > 0 && !c.Quiet && !c.JSON {
+		fmt.Println()
+	}
+	return fixed
+}
+
 func (c *CLI) runPhase(args []string) int {
 	if len(args) < 1 {
 		fmt.Fprintln(os.Stderr, "Usage: minispec phase <spec|requirements|design|implementation|gaps>")
@@ -621,6 +1211,17 @@ func (c *CLI) runPhase(args []string) int {
 
 	ph := phase.New(p)
 	phaseName := args[0]
+	fs := flag.NewFlagSet("phase", flag.ContinueOnError)
+	c.outputFlags(fs)
+	failOn := fs.String("fail-on", "error", "Least severity that fails the phase (error|warning|info)")
+	if err := fs.Parse(args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if ph.FailOn, err = validate.ParseSeverity(*failOn); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --fail-on: %v\n", err)
+		return 1
+	}
 
 	var result *phase.Result
 
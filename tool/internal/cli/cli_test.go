@@ -0,0 +1,89 @@
+// CRC: crc-CLI.md | R133
+package cli
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+
+	"github.com/zot/minispec/internal/query"
+)
+
+func TestSplitWhere(t *testing.T) {
+	for _, args := range [][]string{
+		{"requirements", "--where", "priority == high"},
+		{"requirements", "-where", "priority == high"},
+		{"--where=priority == high", "requirements"},
+		{"requirements", "-where=priority == high"},
+	} {
+		rest, where, err := splitWhere(args)
+		if err != nil {
+			t.Fatalf("splitWhere(%q): %v", args, err)
+		}
+		if where == nil || !reflect.DeepEqual(rest, []string{"requirements"}) {
+			t.Errorf("splitWhere(%q) = %q, %v", args, rest, where)
+		}
+	}
+
+	// where is a search term or a subcommand argument, not the flag.
+	for _, args := range [][]string{
+		{"search", "where"},
+		{"search", "where=x", "--json"},
+	} {
+		rest, where, err := splitWhere(args)
+		if err != nil || where != nil || !reflect.DeepEqual(rest, args) {
+			t.Errorf("splitWhere(%q) = %q, %v, %v", args, rest, where, err)
+		}
+	}
+
+	if _, _, err := splitWhere([]string{"requirements", "--where"}); err == nil {
+		t.Error("--where without an expression accepted")
+	}
+}
+
+func TestListRecords(t *testing.T) {
+	c := &CLI{}
+	where, err := query.ParseWhere(`id != "R2"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var printed []string
+	if code := listValues(c, where, "id", []string{"R1", "R2", "R3"}, nil); code != 0 {
+		t.Errorf("listValues exit = %d", code)
+	}
+	records := []struct{ ID string }{{"R1"}, {"R2"}, {"R3"}}
+	code := listRecords(c, where, records, nil, func(rs []struct{ ID string }) {
+		for _, r := range rs {
+			printed = append(printed, r.ID)
+		}
+	})
+	if code != 0 || !reflect.DeepEqual(printed, []string{"R1", "R3"}) {
+		t.Errorf("listRecords = %d, printed %v", code, printed)
+	}
+
+	// a query that does not list records rejects --where before printing
+	if code := showResult(c, where, "R1", nil, func(string) { t.Error("printed a rejected result") }); code != 1 {
+		t.Errorf("showResult with --where exit = %d", code)
+	}
+	if code := showResult(c, nil, "R1", nil, func(string) {}); code != 0 {
+		t.Errorf("showResult exit = %d", code)
+	}
+}
+
+func TestOutputFlags(t *testing.T) {
+	// after the command: validate --json
+	c := &CLI{}
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	c.outputFlags(fs)
+	if err := fs.Parse([]string{"--json", "--quiet"}); err != nil || !c.JSON || !c.Quiet {
+		t.Errorf("--json --quiet after the command: %v, %+v", err, c)
+	}
+
+	// before the command: minispec --json validate keeps JSON on
+	c = &CLI{JSON: true}
+	fs = flag.NewFlagSet("validate", flag.ContinueOnError)
+	c.outputFlags(fs)
+	if err := fs.Parse(nil); err != nil || !c.JSON {
+		t.Errorf("global --json reset by the command's flags: %v, %+v", err, c)
+	}
+}
@@ -0,0 +1,157 @@
+// CRC: crc-Export.md | Seq: seq-export.md | R129
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/zot/minispec/internal/query"
+)
+
+// Formats lists the graph formats Graph writes.
+var Formats = []string{"dot", "mermaid", "graphml"}
+
+// Graph writes g in the given format: dot (Graphviz), mermaid (flowchart)
+// or graphml. R129
+func Graph(w io.Writer, g *query.TraceGraph, format string) error {
+	switch format {
+	case "dot":
+		return writeDOT(w, g)
+	case "mermaid":
+		return writeMermaid(w, g)
+	case "graphml":
+		return writeGraphML(w, g)
+	}
+	return fmt.Errorf("unknown graph format %q (want %s)", format, strings.Join(Formats, ", "))
+}
+
+// dotShapes gives each node kind its own shape.
+var dotShapes = map[string]string{
+	"spec":        "note",
+	"requirement": "box",
+	"crc":         "component",
+	"sequence":    "cds",
+	"design":      "folder",
+	"code":        "ellipse",
+}
+
+// writeDOT writes a Graphviz digraph: retired requirements are dashed and
+// gray, uncovered ones red, unchecked artifacts edges dashed.
+func writeDOT(w io.Writer, g *query.TraceGraph) error {
+	var sb strings.Builder
+	sb.WriteString("digraph minispec {\n  rankdir=LR;\n  node [fontname=\"Helvetica\"];\n")
+	for _, n := range g.Nodes {
+		attrs := []string{"label=" + strconv.Quote(n.Label), "shape=" + dotShapes[n.Kind]}
+		switch {
+		case n.Retired:
+			attrs = append(attrs, `style=dashed`, `color=gray`, `fontcolor=gray`)
+		case n.Uncovered:
+			attrs = append(attrs, `color=red`, `fontcolor=red`)
+		}
+		fmt.Fprintf(&sb, "  %s [%s];\n", strconv.Quote(n.ID), strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		attrs := []string{"label=" + strconv.Quote(e.Kind)}
+		if e.Unchecked {
+			attrs = append(attrs, `style=dashed`)
+		}
+		fmt.Fprintf(&sb, "  %s -> %s [%s];\n", strconv.Quote(e.From), strconv.Quote(e.To), strings.Join(attrs, ", "))
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeMermaid writes a Mermaid flowchart. Node IDs are numbered since
+// Mermaid IDs cannot hold paths; unchecked artifacts edges are dotted.
+func writeMermaid(w io.Writer, g *query.TraceGraph) error {
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	ids := make(map[string]string)
+	var retired, uncovered []string
+	for i, n := range g.Nodes {
+		id := "n" + strconv.Itoa(i)
+		ids[n.ID] = id
+		label := strings.ReplaceAll(n.Label, `"`, "#quot;")
+		left, right := "[", "]"
+		switch n.Kind {
+		case "spec":
+			left, right = "[/", "/]"
+		case "crc":
+			left, right = "[[", "]]"
+		case "sequence":
+			left, right = "{{", "}}"
+		case "code":
+			left, right = "(", ")"
+		}
+		fmt.Fprintf(&sb, "  %s%s\"%s\"%s\n", id, left, label, right)
+		switch {
+		case n.Retired:
+			retired = append(retired, id)
+		case n.Uncovered:
+			uncovered = append(uncovered, id)
+		}
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if e.Unchecked {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&sb, "  %s %s|%s| %s\n", ids[e.From], arrow, e.Kind, ids[e.To])
+	}
+	if len(retired) > 0 {
+		sb.WriteString("  classDef retired stroke:#999,color:#999,stroke-dasharray:4 4\n")
+		fmt.Fprintf(&sb, "  class %s retired\n", strings.Join(retired, ","))
+	}
+	if len(uncovered) > 0 {
+		sb.WriteString("  classDef uncovered stroke:#d00,color:#d00\n")
+		fmt.Fprintf(&sb, "  class %s uncovered\n", strings.Join(uncovered, ","))
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeGraphML writes GraphML with the node kind, label, retired and
+// uncovered flags as node data and the edge kind and unchecked flag as
+// edge data.
+func writeGraphML(w io.Writer, g *query.TraceGraph) error {
+	var sb strings.Builder
+	esc := func(s string) string {
+		var b strings.Builder
+		xml.EscapeText(&b, []byte(s))
+		return b.String()
+	}
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	for _, k := range []struct{ id, on, name, typ string }{
+		{"kind", "node", "kind", "string"},
+		{"label", "node", "label", "string"},
+		{"retired", "node", "retired", "boolean"},
+		{"uncovered", "node", "uncovered", "boolean"},
+		{"ekind", "edge", "kind", "string"},
+		{"unchecked", "edge", "unchecked", "boolean"},
+	} {
+		fmt.Fprintf(&sb, "  <key id=%q for=%q attr.name=%q attr.type=%q/>\n", k.id, k.on, k.name, k.typ)
+	}
+	sb.WriteString(`  <graph id="minispec" edgedefault="directed">` + "\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&sb, "    <node id=\"%s\">\n", esc(n.ID))
+		fmt.Fprintf(&sb, "      <data key=\"kind\">%s</data>\n", n.Kind)
+		fmt.Fprintf(&sb, "      <data key=\"label\">%s</data>\n", esc(n.Label))
+		fmt.Fprintf(&sb, "      <data key=\"retired\">%t</data>\n", n.Retired)
+		fmt.Fprintf(&sb, "      <data key=\"uncovered\">%t</data>\n", n.Uncovered)
+		sb.WriteString("    </node>\n")
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&sb, "    <edge source=\"%s\" target=\"%s\">\n", esc(e.From), esc(e.To))
+		fmt.Fprintf(&sb, "      <data key=\"ekind\">%s</data>\n", e.Kind)
+		fmt.Fprintf(&sb, "      <data key=\"unchecked\">%t</data>\n", e.Unchecked)
+		sb.WriteString("    </edge>\n")
+	}
+	sb.WriteString("  </graph>\n</graphml>\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,99 @@
+// CRC: crc-Export.md | R129
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/zot/minispec/internal/query"
+)
+
+func testGraph() *query.TraceGraph {
+	return &query.TraceGraph{
+		Nodes: []query.GraphNode{
+			{ID: "code:src/a&b.go", Kind: "code", Label: "src/a&b.go"},
+			{ID: "design:crc-Store.md", Kind: "crc", Label: "crc-Store.md"},
+			{ID: "req:R1", Kind: "requirement", Label: "R1"},
+			{ID: "req:R2", Kind: "requirement", Label: "R2", Uncovered: true},
+			{ID: "req:R3", Kind: "requirement", Label: "R3", Retired: true},
+			{ID: "spec:specs/store.md", Kind: "spec", Label: "specs/store.md"},
+		},
+		Edges: []query.GraphEdge{
+			{From: "spec:specs/store.md", To: "req:R1", Kind: "source"},
+			{From: "req:R1", To: "design:crc-Store.md", Kind: "requirements"},
+			{From: "design:crc-Store.md", To: "code:src/a&b.go", Kind: "artifacts", Unchecked: true},
+		},
+	}
+}
+
+func TestGraphDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Graph(&buf, testGraph(), "dot"); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`digraph minispec {`,
+		`"req:R2" [label="R2", shape=box, color=red, fontcolor=red];`,
+		`"req:R3" [label="R3", shape=box, style=dashed, color=gray, fontcolor=gray];`,
+		`"design:crc-Store.md" -> "code:src/a&b.go" [label="artifacts", style=dashed];`,
+		`"req:R1" -> "design:crc-Store.md" [label="requirements"];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestGraphMermaid(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Graph(&buf, testGraph(), "mermaid"); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"flowchart LR\n",
+		`n1[["crc-Store.md"]]`,
+		`n5[/"specs/store.md"/]`,
+		"n1 -.->|artifacts| n0",
+		"n5 -->|source| n2",
+		"class n4 retired",
+		"class n3 uncovered",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestGraphGraphML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Graph(&buf, testGraph(), "graphml"); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Nodes []struct {
+			ID string `xml:"id,attr"`
+		} `xml:"graph>node"`
+		Edges []struct {
+			Source string `xml:"source,attr"`
+		} `xml:"graph>edge"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("GraphML is not well-formed: %v\n%s", err, buf.String())
+	}
+	if len(doc.Nodes) != 6 || len(doc.Edges) != 3 {
+		t.Errorf("got %d nodes, %d edges, want 6, 3", len(doc.Nodes), len(doc.Edges))
+	}
+	if doc.Nodes[0].ID != "code:src/a&b.go" {
+		t.Errorf("node id = %q, want it unescaped after parsing", doc.Nodes[0].ID)
+	}
+}
+
+func TestGraphUnknownFormat(t *testing.T) {
+	if err := Graph(&bytes.Buffer{}, testGraph(), "svg"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
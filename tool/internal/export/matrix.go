@@ -0,0 +1,80 @@
+// CRC: crc-Export.md | Seq: seq-export.md | R136
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/zot/minispec/internal/query"
+)
+
+// MatrixFormats lists the matrix formats Matrix writes.
+var MatrixFormats = []string{"md", "csv", "json"}
+
+// matrixColumns are the matrix column headings, in order.
+var matrixColumns = []string{"Requirement", "Text", "Feature", "Status", "Source", "CRC Cards", "Sequences", "Code Files", "Test Designs", "Artifacts", "Gaps"}
+
+// Matrix writes the traceability matrix as a Markdown table, CSV (lists
+// separated by "; ") or JSON. R136
+func Matrix(w io.Writer, rows []query.MatrixRow, format string) error {
+	switch format {
+	case "md":
+		return writeMatrixMarkdown(w, rows)
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write(matrixColumns)
+		for _, r := range rows {
+			cw.Write(matrixCells(r, "; "))
+		}
+		cw.Flush()
+		return cw.Error()
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(rows)
+	}
+	return fmt.Errorf("unknown matrix format %q (want %s)", format, strings.Join(MatrixFormats, ", "))
+}
+
+// matrixCells returns a row's cells with lists joined by sep. The Artifacts
+// cell is checked/total, empty when the cards list no code files.
+func matrixCells(r query.MatrixRow, sep string) []string {
+	artifacts := ""
+	if r.ArtifactsTotal > 0 {
+		artifacts = strconv.Itoa(r.ArtifactsChecked) + "/" + strconv.Itoa(r.ArtifactsTotal)
+	}
+	return []string{
+		r.Requirement, r.Text, r.Feature, r.Status, r.Source,
+		strings.Join(r.CRCCards, sep),
+		strings.Join(r.Sequences, sep),
+		strings.Join(r.CodeFiles, sep),
+		strings.Join(r.TestDesigns, sep),
+		artifacts,
+		strings.Join(r.Gaps, sep),
+	}
+}
+
+// writeMatrixMarkdown writes a GitHub-flavored table, escaping pipes and
+// joining lists with <br>.
+func writeMatrixMarkdown(w io.Writer, rows []query.MatrixRow) error {
+	var sb strings.Builder
+	line := func(cells []string) {
+		sb.WriteString("|")
+		for _, c := range cells {
+			sb.WriteString(" " + strings.ReplaceAll(c, "|", `\|`) + " |")
+		}
+		sb.WriteString("\n")
+	}
+	line(matrixColumns)
+	sb.WriteString(strings.Repeat("|---", len(matrixColumns)) + "|\n")
+	for _, r := range rows {
+		line(matrixCells(r, "<br>"))
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,71 @@
+// CRC: crc-Export.md | R136
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zot/minispec/internal/query"
+)
+
+var testRows = []query.MatrixRow{
+	{
+		Requirement: "R1", Text: "parse a|b", Feature: "Core", Status: "open", Source: "specs/core.md",
+		CRCCards: []string{"crc-Parser.md", "crc-Store.md"}, CodeFiles: []string{"src/parser.go"},
+		ArtifactsChecked: 1, ArtifactsTotal: 2, Gaps: []string{"D1 open"},
+	},
+	{Requirement: "R2", Text: "old", Feature: "Core", Status: "retired"},
+}
+
+func TestMatrixMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Matrix(&buf, testRows, "md"); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want header, rule and 2 rows:\n%s", len(lines), buf.String())
+	}
+	want := `| R1 | parse a\|b | Core | open | specs/core.md | crc-Parser.md<br>crc-Store.md |  | src/parser.go |  | 1/2 | D1 open |`
+	if lines[2] != want {
+		t.Errorf("row = %s\nwant  %s", lines[2], want)
+	}
+}
+
+func TestMatrixCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Matrix(&buf, testRows, "csv"); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || !reflect.DeepEqual(records[0], matrixColumns) {
+		t.Fatalf("records = %v", records)
+	}
+	if records[1][5] != "crc-Parser.md; crc-Store.md" || records[2][3] != "retired" || records[2][9] != "" {
+		t.Errorf("rows = %v", records[1:])
+	}
+}
+
+func TestMatrixJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Matrix(&buf, testRows, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var rows []query.MatrixRow
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rows, testRows) {
+		t.Errorf("round trip = %+v", rows)
+	}
+	if err := Matrix(&buf, testRows, "xlsx"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -1,41 +1,92 @@
-// CRC: crc-Parser.md | Seq: seq-parse.md
+// CRC: crc-Parser.md | Seq: seq-parse.md | R94, R99, R100, R104, R115
 package parser
 
 import (
-	"bufio"
-	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 )
 
 var (
-	crcNameRe     = regexp.MustCompile(`^# (.+)`)
-	crcReqsRe     = regexp.MustCompile(`^\*\*Requirements:\*\*\s*(.*)`)
-	crcSeqHdrRe   = regexp.MustCompile(`^## Sequences`)
-	crcListItemRe = regexp.MustCompile(`^- (.+\.md)`)
+	crcNameRe       = regexp.MustCompile(`^# (.+)`)
+	crcNamePrefixRe = regexp.MustCompile(`^(?i:CRC)\s*:\s*`)
+	crcReqsRe       = regexp.MustCompile(`^\*\*Requirements:\*\*\s*(.*)`)
+	crcTypeRe       = regexp.MustCompile(`^\*\*Type(?:\*\*:|:\*\*)\s*(.*)`)
+	crcSectionRe    = regexp.MustCompile(`^##\s+(.+?)\s*$`)
+	crcListItemRe   = regexp.MustCompile(`^- (.+\.md)`)
+	crcItemRe       = regexp.MustCompile(`^[-*]\s+(.+?)\s*$`)
+	// crcCollabRe splits a Collaborators item into its name and the note
+	// after it: "- Contact (stores)", "- Parser: to parse design files".
+	crcCollabRe = regexp.MustCompile("^[-*]\\s+`?([A-Za-z_][\\w./-]*)`?\\s*(.*)$")
+	// declNameRe finds a declared method or function name: an identifier
+	// directly followed by its parameter list (optionally generic).
+	declNameRe = regexp.MustCompile(`([A-Za-z_]\w*)\s*(?:<[^<>()]*>)?\s*\(`)
 )
 
-// ParseCRCCard parses a CRC card file
+// declKeywords are identifiers followed by "(" that never name a declaration.
+var declKeywords = map[string]bool{
+	"func": true, "function": true, "if": true, "for": true, "while": true,
+	"switch": true, "catch": true, "return": true,
+}
+
+// CardName returns the class a CRC card file stands for
+// (crc-ContactStore.md -> ContactStore).
+func CardName(path string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "crc-"), ".md")
+}
+
+// parseCollaborator parses one Collaborators list item, or returns false.
+func parseCollaborator(line string, lineNum int) (Collaborator, bool) {
+	m := crcCollabRe.FindStringSubmatch(line)
+	if m == nil {
+		return Collaborator{}, false
+	}
+	note := strings.TrimSpace(strings.TrimLeft(m[2], ":—–- "))
+	if strings.HasPrefix(note, "(") && strings.HasSuffix(note, ")") {
+		note = strings.TrimSpace(note[1 : len(note)-1])
+	}
+	return Collaborator{Name: m[1], Note: note, Line: lineNum}, true
+}
+
+// ParseCRCCard parses a CRC card file: the name (without a "CRC: " heading
+// prefix), Type and Requirements fields, the Knows/Does/Responsibilities,
+// Collaborators, Interface and Sequences sections, and the line range of
+// every ## section. R115
 func ParseCRCCard(path string) (CRCCard, error) {
-	file, err := os.Open(path)
+	lines, err := ReadLines(path)
 	if err != nil {
 		return CRCCard{}, err
 	}
-	defer file.Close()
 
 	card := CRCCard{Path: path}
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
-	inSequences := false
+	section := "" // current ## section title
+	inFence := false
+	var iface []string
+
+	for i, line := range lines {
+		lineNum := i + 1
+		// A section ends at its last non-blank line before the next ## heading
+		if strings.TrimSpace(line) != "" && len(card.Sections) > 0 && (inFence || !crcSectionRe.MatchString(line)) {
+			card.Sections[len(card.Sections)-1].EndLine = lineNum
+		}
 
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
+		// Collect declared signatures from the fenced Interface block
+		if section == "Interface" && fenceRe.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			iface = append(iface, line)
+			if sig, ok := interfaceSignature(lines, i); ok {
+				card.Interface = append(card.Interface, sig)
+			}
+			continue
+		}
 
 		// Extract name from first # heading
 		if card.Name == "" {
 			if matches := crcNameRe.FindStringSubmatch(line); matches != nil {
-				card.Name = strings.TrimSpace(matches[1])
+				card.Name = crcNamePrefixRe.ReplaceAllString(strings.TrimSpace(matches[1]), "")
 				continue
 			}
 		}
@@ -55,26 +106,43 @@ func ParseCRCCard(path string) (CRCCard, error) {
 			}
 			continue
 		}
-
-		// Detect Sequences section
-		if crcSeqHdrRe.MatchString(line) {
-			inSequences = true
+		if matches := crcTypeRe.FindStringSubmatch(line); matches != nil {
+			card.Type = strings.TrimSpace(matches[1])
 			continue
 		}
 
-		// New section ends Sequences parsing
-		if strings.HasPrefix(line, "## ") {
-			inSequences = false
+		// A ## heading starts a new section
+		if matches := crcSectionRe.FindStringSubmatch(line); matches != nil {
+			section = matches[1]
+			card.Sections = append(card.Sections, CRCSection{Title: section, Line: lineNum, EndLine: lineNum})
 			continue
 		}
 
-		// Extract sequence refs when in Sequences section
-		if inSequences {
+		switch section {
+		case "Collaborators":
+			if c, ok := parseCollaborator(line, lineNum); ok {
+				card.Collaborators = append(card.Collaborators, c)
+			}
+		case "Sequences":
 			if matches := crcListItemRe.FindStringSubmatch(line); matches != nil {
 				card.Sequences = append(card.Sequences, strings.TrimSpace(matches[1]))
 			}
+		case "Knows", "Does", "Responsibilities":
+			matches := crcItemRe.FindStringSubmatch(line)
+			if matches == nil {
+				continue
+			}
+			switch section {
+			case "Knows":
+				card.Knows = append(card.Knows, matches[1])
+			case "Does":
+				card.Does = append(card.Does, matches[1])
+			default:
+				card.Responsibilities = append(card.Responsibilities, matches[1])
+			}
 		}
 	}
+	card.InterfaceSource = strings.Join(iface, "\n")
 
-	return card, scanner.Err()
+	return card, nil
 }
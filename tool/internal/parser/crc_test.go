@@ -0,0 +1,67 @@
+// CRC: crc-Parser.md | R100, R115
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCRCCardCollaborators(t *testing.T) {
+	path := writeTempNamed(t, "crc-Store.md", "# Store\n**Requirements:** R1\n\n"+
+		"## Collaborators\n"+
+		"- Contact (stores)\n"+
+		"- Parser: to parse design files\n"+
+		"- `os/filepath`\n\n"+
+		"## Sequences\n- seq-crud.md\n")
+	card, err := ParseCRCCard(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Collaborator{
+		{Name: "Contact", Note: "stores", Line: 5},
+		{Name: "Parser", Note: "to parse design files", Line: 6},
+		{Name: "os/filepath", Line: 7},
+	}
+	if !reflect.DeepEqual(card.Collaborators, want) {
+		t.Errorf("collaborators = %+v, want %+v", card.Collaborators, want)
+	}
+	if !reflect.DeepEqual(card.Sequences, []string{"seq-crud.md"}) {
+		t.Errorf("sequences = %v", card.Sequences)
+	}
+	if got := CardName(path); got != "Store" {
+		t.Errorf("CardName = %q", got)
+	}
+}
+
+func TestParseCRCCardSections(t *testing.T) {
+	path := writeTempNamed(t, "crc-Store.md", "# CRC: Store\n"+
+		"**Type:** service\n"+
+		"**Requirements:** R1, R2\n\n"+
+		"## Knows\n- contacts: map\n\n"+
+		"## Does\n- add(c)\n- remove(id)\n\n"+
+		"## Interface\n```go\nfunc Add(c Contact) error\n```\n")
+	card, err := ParseCRCCard(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Name != "Store" || card.Type != "service" {
+		t.Errorf("name, type = %q, %q", card.Name, card.Type)
+	}
+	if !reflect.DeepEqual(card.Knows, []string{"contacts: map"}) {
+		t.Errorf("knows = %v", card.Knows)
+	}
+	if !reflect.DeepEqual(card.Does, []string{"add(c)", "remove(id)"}) {
+		t.Errorf("does = %v", card.Does)
+	}
+	want := []CRCSection{
+		{Title: "Knows", Line: 5, EndLine: 6},
+		{Title: "Does", Line: 8, EndLine: 10},
+		{Title: "Interface", Line: 12, EndLine: 15},
+	}
+	if !reflect.DeepEqual(card.Sections, want) {
+		t.Errorf("sections = %+v, want %+v", card.Sections, want)
+	}
+	if card.InterfaceSource != "func Add(c Contact) error" {
+		t.Errorf("interface source = %q", card.InterfaceSource)
+	}
+}
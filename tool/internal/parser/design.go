@@ -1,9 +1,7 @@
-// CRC: crc-Parser.md | Seq: seq-parse.md | R71, R73, R74, R75
+// CRC: crc-Parser.md | Seq: seq-parse.md | R71, R73, R74, R75, R94
 package parser
 
 import (
-	"bufio"
-	"os"
 	"regexp"
 	"strings"
 )
@@ -23,21 +21,17 @@ var (
 // Legacy: - design.md\n  - [x] code.ts
 // Inline: - [x] design.md → code.ts, code2.ts
 func ParseArtifacts(path string) ([]Artifact, error) {
-	file, err := os.Open(path)
+	lines, err := ReadLines(path)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
 	var artifacts []Artifact
 	var current *Artifact
 	inArtifacts := false
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
 
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
+	for i, line := range lines {
+		lineNum := i + 1
 
 		if matches := sectionRe.FindStringSubmatch(line); matches != nil {
 			section := strings.TrimSpace(matches[1])
@@ -68,7 +62,7 @@ func ParseArtifacts(path string) ([]Artifact, error) {
 				current = nil
 			}
 
-			artifact := Artifact{DesignFile: designFile}
+			artifact := Artifact{DesignFile: designFile, Line: lineNum}
 
 			if codeFilesStr != "" {
 				for _, cf := range strings.Split(codeFilesStr, ",") {
@@ -92,7 +86,7 @@ func ParseArtifacts(path string) ([]Artifact, error) {
 			if current != nil {
 				artifacts = append(artifacts, *current)
 			}
-			current = &Artifact{DesignFile: matches[1]}
+			current = &Artifact{DesignFile: matches[1], Line: lineNum}
 			continue
 		}
 
@@ -109,7 +103,7 @@ func ParseArtifacts(path string) ([]Artifact, error) {
 		artifacts = append(artifacts, *current)
 	}
 
-	return artifacts, scanner.Err()
+	return artifacts, nil
 }
 
 // ParseGaps parses the Gaps section of design.md.
@@ -117,20 +111,16 @@ func ParseArtifacts(path string) ([]Artifact, error) {
 // are written without checkboxes (R74, R75) but legacy `- [ ] A1: ...`
 // is still parsed for back-compat with HasCheckbox=true.
 func ParseGaps(path string) ([]Gap, error) {
-	file, err := os.Open(path)
+	lines, err := ReadLines(path)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
 	var gaps []Gap
 	inGaps := false
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
 
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
+	for i, line := range lines {
+		lineNum := i + 1
 
 		if matches := sectionRe.FindStringSubmatch(line); matches != nil {
 			section := strings.TrimSpace(matches[1])
@@ -171,5 +161,5 @@ func ParseGaps(path string) ([]Gap, error) {
 		}
 	}
 
-	return gaps, scanner.Err()
+	return gaps, nil
 }
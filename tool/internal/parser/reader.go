@@ -0,0 +1,41 @@
+// CRC: crc-Parser.md | Seq: seq-parse.md | R94
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+)
+
+// ErrBinary is returned by ReadLines, wrapped in an *os.PathError, for files
+// that look binary.
+var ErrBinary = errors.New("binary file")
+
+// binarySniffLen is how much of a file is checked for NUL bytes, as git does.
+const binarySniffLen = 8000
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ReadLines reads a text file into lines for the parsers. It strips a leading
+// UTF-8 BOM, accepts LF and CRLF line endings, has no line length limit, and
+// rejects files with a NUL byte near the start with ErrBinary. Line i+1 of the
+// file is lines[i], matching the line numbers parsers record.
+func ReadLines(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(data[:min(len(data), binarySniffLen)], 0) >= 0 {
+		return nil, &os.PathError{Op: "read", Path: path, Err: ErrBinary}
+	}
+	text := string(bytes.TrimPrefix(data, utf8BOM))
+	if text == "" {
+		return nil, nil
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines, nil
+}
@@ -0,0 +1,35 @@
+// CRC: crc-Parser.md | R94
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadLines(t *testing.T) {
+	long := strings.Repeat("x", 200_000)
+	path := writeTempNamed(t, "crlf.md", "\xEF\xBB\xBF# CRC: Store\r\n**Requirements:** R1\r\n"+long+"\r\nlast")
+	lines, err := ReadLines(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"# CRC: Store", "**Requirements:** R1", long, "last"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines differ: got %d lines, first %q", len(lines), lines[0])
+	}
+
+	card, err := ParseCRCCard(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(card.Requirements, []string{"R1"}) || card.ReqLine != 2 {
+		t.Errorf("card = %+v", card)
+	}
+
+	bin := writeTempNamed(t, "logo.png", "\x89PNG\x00\x00// CRC: crc-X.md")
+	if _, err := ParseTraceability(bin, "", ""); !errors.Is(err, ErrBinary) {
+		t.Errorf("binary file error = %v, want ErrBinary", err)
+	}
+}
@@ -1,40 +1,43 @@
-// CRC: crc-Parser.md | Seq: seq-parse.md | R77
+// CRC: crc-Parser.md | Seq: seq-parse.md | R77, R94, R117, R121, R124, R134, R135
 package parser
 
 import (
-	"bufio"
-	"os"
 	"regexp"
 	"strings"
 )
 
 var (
 	featureRe = regexp.MustCompile(`^## Feature:\s*(.+)`)
-	sourceRe  = regexp.MustCompile(`^\*\*Source:\*\*\s*(.+)`)
+	sourceRe  = regexp.MustCompile(`^\*\*Source:\*\*\s*(.+?)\s*(?:<!--\s*spec-hash:\s*(\w+)\s*-->)?\s*$`)
 	// requirementRe matches `- **R1:** text` and `- **~~R1:~~** text` (retired form).
 	requirementRe = regexp.MustCompile(`^- \*\*(~~)?R(\d+):(?:~~)?\*\*\s*(.+)`)
 	inferredRe    = regexp.MustCompile(`^\(inferred\)\s*`)
-	retiredPrefix = regexp.MustCompile(`^\(Retired\s+T\d+[^)]*\)\s*`)
+	retiredPrefix = regexp.MustCompile(`^\(Retired\s+(T\d+)[^)]*\)\s*`)
+	// retiredSeeRe finds the replacement in a retired marker's "see R20".
+	retiredSeeRe = regexp.MustCompile(`\bsee\s+(R\d+)\b`)
+	// reqMetaRe matches one leading metadata tag: [P1], [priority: high],
+	// [status: deferred], [source: specs/auth.md#reset] or [source: #reset].
+	reqMetaRe = regexp.MustCompile(`^\[(?:(P\d+)|(priority|status)\s*:\s*([\w-]+)|source\s*:\s*([^\]\s]+))\]\s*`)
+	// reqTagsRe matches the run of #tags that ends a requirement line.
+	reqTagsRe = regexp.MustCompile(`(?:\s+#[A-Za-z][\w-]*)+\s*$`)
 )
 
-// ParseRequirements parses a requirements.md file
+// ParseRequirements parses a requirements.md file, recording each
+// requirement's `## Feature:` section (R134)
 func ParseRequirements(path string) ([]Requirement, error) {
-	file, err := os.Open(path)
+	lines, err := ReadLines(path)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
 	var requirements []Requirement
-	var currentSource string
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
+	var currentSource, currentFeature string
 
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
+	for i, line := range lines {
+		lineNum := i + 1
 
-		if featureRe.MatchString(line) {
+		if matches := featureRe.FindStringSubmatch(line); matches != nil {
+			currentFeature = strings.TrimSpace(matches[1])
 			currentSource = ""
 			continue
 		}
@@ -52,8 +55,15 @@ func ParseRequirements(path string) ([]Requirement, error) {
 		retired := matches[1] != ""
 		text := strings.TrimSpace(matches[3])
 		// R77: strip a leading "(Retired Tn — see Rxxx)" or "(Retired Tn — no replacement)"
-		// marker if present, leaving the original text.
+		// marker if present, leaving the original text and keeping Tn and Rxxx (R135).
+		var retiredBy, replacedBy string
 		if retired {
+			if m := retiredPrefix.FindStringSubmatch(text); m != nil {
+				retiredBy = m[1]
+				if see := retiredSeeRe.FindStringSubmatch(m[0]); see != nil {
+					replacedBy = see[1]
+				}
+			}
 			text = retiredPrefix.ReplaceAllString(text, "")
 		}
 
@@ -63,15 +73,82 @@ func ParseRequirements(path string) ([]Requirement, error) {
 			text = inferredRe.ReplaceAllString(text, "")
 		}
 
-		requirements = append(requirements, Requirement{
-			ID:       "R" + matches[2],
-			Text:     text,
-			Source:   currentSource,
-			Inferred: inferred,
-			Retired:  retired,
-			Line:     lineNum,
-		})
+		r := Requirement{
+			ID:         "R" + matches[2],
+			Feature:    currentFeature,
+			Source:     currentSource,
+			Inferred:   inferred,
+			Retired:    retired,
+			RetiredBy:  retiredBy,
+			ReplacedBy: replacedBy,
+			Line:       lineNum,
+		}
+		r.Text = parseRequirementMetadata(&r, text)
+		requirements = append(requirements, r)
+	}
+
+	return requirements, nil
+}
+
+// ParseFeatureSources lists the **Source:** line of each feature in
+// requirements.md with its recorded spec fingerprint. R124
+func ParseFeatureSources(path string) ([]FeatureSource, error) {
+	lines, err := ReadLines(path)
+	if err != nil {
+		return nil, err
 	}
 
-	return requirements, scanner.Err()
+	var sources []FeatureSource
+	feature := ""
+	for i, line := range lines {
+		lineNum := i + 1
+		if matches := featureRe.FindStringSubmatch(line); matches != nil {
+			feature = strings.TrimSpace(matches[1])
+			continue
+		}
+		if matches := sourceRe.FindStringSubmatch(line); matches != nil {
+			sources = append(sources, FeatureSource{
+				Feature:     feature,
+				Source:      matches[1],
+				Fingerprint: matches[2],
+				Line:        lineNum,
+			})
+		}
+	}
+	return sources, nil
+}
+
+// parseRequirementMetadata fills r's priority, status and tags from leading
+// [P1] / [priority: x] / [status: x] markers and trailing #tags, and returns
+// the text without them. A [source: ...] marker overrides the feature's
+// Source; a bare #anchor applies to the feature's spec file. R117, R121
+func parseRequirementMetadata(r *Requirement, text string) string {
+	for {
+		m := reqMetaRe.FindStringSubmatch(text)
+		if m == nil {
+			break
+		}
+		switch {
+		case m[1] != "":
+			r.Priority = m[1]
+		case m[4] != "":
+			if strings.HasPrefix(m[4], "#") {
+				r.Source = r.SourceFile() + m[4]
+			} else {
+				r.Source = m[4]
+			}
+		case strings.EqualFold(m[2], "priority"):
+			r.Priority = m[3]
+		default:
+			r.Status = strings.ToLower(m[3])
+		}
+		text = text[len(m[0]):]
+	}
+	if tags := reqTagsRe.FindString(text); tags != "" {
+		for _, tag := range strings.Fields(tags) {
+			r.Tags = append(r.Tags, strings.TrimPrefix(tag, "#"))
+		}
+		text = strings.TrimSpace(strings.TrimSuffix(text, tags))
+	}
+	return text
 }
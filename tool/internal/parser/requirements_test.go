@@ -0,0 +1,67 @@
+// CRC: crc-Parser.md | R117, R134
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRequirementsMetadata(t *testing.T) {
+	path := writeTempNamed(t, "requirements.md", `# Requirements
+
+## Feature: Test
+**Source:** specs/test.md
+
+- **R1:** [P1] [status: Deferred] encrypt stored contacts #security #storage
+- **R2:** (inferred) [priority: high] colors like #fff stay in the text
+- **R3:** plain requirement
+`)
+	reqs, err := ParseRequirements(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r1 := reqs[0]
+	if r1.Text != "encrypt stored contacts" || r1.Priority != "P1" || r1.Status != "deferred" || !r1.Deferred() {
+		t.Errorf("R1 = %+v", r1)
+	}
+	if !reflect.DeepEqual(r1.Tags, []string{"security", "storage"}) {
+		t.Errorf("R1 tags = %v", r1.Tags)
+	}
+	r2 := reqs[1]
+	if r2.Text != "colors like #fff stay in the text" || r2.Priority != "high" || !r2.Inferred || r2.Tags != nil {
+		t.Errorf("R2 = %+v", r2)
+	}
+	if reqs[2].StatusOrOpen() != "open" || reqs[2].Deferred() {
+		t.Errorf("R3 status = %q", reqs[2].Status)
+	}
+}
+
+func TestParseRequirementsFeature(t *testing.T) {
+	path := writeTempNamed(t, "requirements.md", `# Requirements
+
+- **R1:** before any feature
+
+## Feature: Auth
+**Source:** specs/auth.md
+
+- **R2:** login
+
+## Feature: Storage
+
+- **R3:** persist
+`)
+	reqs, err := ParseRequirements(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range reqs {
+		got = append(got, r.ID+"="+r.Feature)
+	}
+	if want := []string{"R1=", "R2=Auth", "R3=Storage"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("features = %v, want %v", got, want)
+	}
+	if reqs[2].Source != "" {
+		t.Errorf("R3 source = %q, want none", reqs[2].Source)
+	}
+}
@@ -1,4 +1,4 @@
-// CRC: crc-Parser.md | R73, R74, R75, R77
+// CRC: crc-Parser.md | R73, R74, R75, R77, R135
 package parser
 
 import (
@@ -45,6 +45,12 @@ func TestParseRequirements_Retired(t *testing.T) {
 	if !reqs[2].Retired || reqs[2].Text != "gone for good" {
 		t.Errorf("R3 retired/text wrong: %+v", reqs[2])
 	}
+	if reqs[1].RetiredBy != "T1" || reqs[1].ReplacedBy != "R5" {
+		t.Errorf("R2 retirement = %q/%q, want T1/R5", reqs[1].RetiredBy, reqs[1].ReplacedBy)
+	}
+	if reqs[2].RetiredBy != "T2" || reqs[2].ReplacedBy != "" {
+		t.Errorf("R3 retirement = %q/%q, want T2 with no replacement", reqs[2].RetiredBy, reqs[2].ReplacedBy)
+	}
 	if reqs[0].Retired || reqs[3].Retired {
 		t.Errorf("non-retired marked retired")
 	}
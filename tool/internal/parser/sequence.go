@@ -0,0 +1,111 @@
+// CRC: crc-Parser.md | Seq: seq-parse.md | R96
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	fenceRe = regexp.MustCompile("^\\s*```")
+	// seqFenceRe captures a fence's info string; only untagged, text and
+	// sequence fences hold diagrams (a ```yaml block is config, not messages).
+	seqFenceRe = regexp.MustCompile("^\\s*```\\s*(\\w*)")
+	// seqMessageRe matches `A -> B: text` and the reply form `A --> B: text`.
+	seqMessageRe = regexp.MustCompile(`^\s*([A-Za-z_][\w./-]*)\s*(-->|->)\s*([A-Za-z_][\w./-]*)\s*:\s*(.*)$`)
+	// seqSelfRe matches a participant's self note, e.g. `ContactStore: persist`.
+	seqSelfRe    = regexp.MustCompile(`^\s*([A-Za-z_][\w./-]*)\s*:\s*(.+)$`)
+	seqBlockRe   = regexp.MustCompile(`^\s*(alt|loop|opt|par|critical|break)\b\s*(.*)$`)
+	seqElseRe    = regexp.MustCompile(`^\s*else\b\s*(.*)$`)
+	seqEndRe     = regexp.MustCompile(`^\s*end\s*$`)
+	seqNoteRe    = regexp.MustCompile(`^\s*note\b`)
+	seqMethodRe  = regexp.MustCompile(`^([A-Za-z_]\w*)\s*\(`)
+	seqCommentRe = regexp.MustCompile(`\s+//.*$`)
+)
+
+// ParseSequence parses the fenced diagrams of a seq-*.md file into
+// participants, messages and alt/loop/opt blocks. Lines outside diagram
+// fences are ignored. R96
+func ParseSequence(path string) (Sequence, error) {
+	lines, err := ReadLines(path)
+	if err != nil {
+		return Sequence{}, err
+	}
+
+	seq := Sequence{Path: path}
+	seen := make(map[string]bool)
+	addParticipant := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			seq.Participants = append(seq.Participants, name)
+		}
+	}
+	var stack []int // open block indexes, innermost last
+	inFence, inDiagram := false, false
+
+	for i, line := range lines {
+		lineNum := i + 1
+
+		if m := seqFenceRe.FindStringSubmatch(line); m != nil {
+			inFence = !inFence
+			tag := strings.ToLower(m[1])
+			inDiagram = inFence && (tag == "" || tag == "text" || tag == "sequence")
+			stack = stack[:0]
+			continue
+		}
+		if !inDiagram || strings.TrimSpace(line) == "" || seqNoteRe.MatchString(line) {
+			continue
+		}
+
+		block := -1
+		if len(stack) > 0 {
+			block = stack[len(stack)-1]
+		}
+
+		// A bare `break` is a statement (leave the loop), not a break block.
+		if m := seqBlockRe.FindStringSubmatch(line); m != nil && (m[1] != "break" || strings.TrimSpace(m[2]) != "") {
+			seq.Blocks = append(seq.Blocks, SeqBlock{
+				Kind:   m[1],
+				Label:  strings.TrimSpace(m[2]),
+				Line:   lineNum,
+				Parent: block,
+			})
+			stack = append(stack, len(seq.Blocks)-1)
+			continue
+		}
+		if m := seqElseRe.FindStringSubmatch(line); m != nil {
+			if block >= 0 {
+				seq.Blocks[block].Else = append(seq.Blocks[block].Else, lineNum)
+			}
+			continue
+		}
+		if seqEndRe.MatchString(line) {
+			if block >= 0 {
+				seq.Blocks[block].EndLine = lineNum
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+
+		msg := SeqMessage{Line: lineNum, Block: block}
+		if m := seqMessageRe.FindStringSubmatch(line); m != nil {
+			msg.From, msg.To, msg.Reply = m[1], m[3], m[2] == "-->"
+			msg.Text = strings.TrimSpace(seqCommentRe.ReplaceAllString(m[4], ""))
+		} else if m := seqSelfRe.FindStringSubmatch(line); m != nil {
+			msg.From, msg.To = m[1], m[1]
+			msg.Text = strings.TrimSpace(m[2])
+		} else {
+			continue
+		}
+		if !msg.Reply {
+			if m := seqMethodRe.FindStringSubmatch(msg.Text); m != nil {
+				msg.Method = m[1]
+			}
+		}
+		addParticipant(msg.From)
+		addParticipant(msg.To)
+		seq.Messages = append(seq.Messages, msg)
+	}
+
+	return seq, nil
+}
@@ -0,0 +1,67 @@
+// CRC: crc-Parser.md | R96, R99
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSequence(t *testing.T) {
+	path := writeTempNamed(t, "seq-crud.md", "# Sequence: CRUD\n\n"+
+		"```\n"+
+		"User -> App: open contact\n"+
+		"App -> Store: getById(id)  // cached\n"+
+		"alt not found\n"+
+		"    Store --> App: null\n"+
+		"else found\n"+
+		"    loop each field\n"+
+		"        App: render(field)\n"+
+		"        break\n"+
+		"    end\n"+
+		"end\n"+
+		"```\n\n"+
+		"```yaml\n"+
+		"design_dir: design\n"+
+		"```\n")
+	seq, err := ParseSequence(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"User", "App", "Store"}; !reflect.DeepEqual(seq.Participants, want) {
+		t.Errorf("participants = %v, want %v", seq.Participants, want)
+	}
+	if len(seq.Messages) != 4 {
+		t.Fatalf("messages = %+v", seq.Messages)
+	}
+	if m := seq.Messages[1]; m.Method != "getById" || m.Text != "getById(id)" || m.Line != 5 || m.Block != -1 {
+		t.Errorf("call = %+v", m)
+	}
+	if m := seq.Messages[2]; !m.Reply || m.Method != "" || m.Block != 0 {
+		t.Errorf("reply = %+v", m)
+	}
+	if m := seq.Messages[3]; m.From != "App" || m.To != "App" || m.Method != "render" || m.Block != 1 {
+		t.Errorf("self note = %+v", m)
+	}
+	want := []SeqBlock{
+		{Kind: "alt", Label: "not found", Line: 6, EndLine: 13, Else: []int{8}, Parent: -1},
+		{Kind: "loop", Label: "each field", Line: 9, EndLine: 12, Parent: 0},
+	}
+	if !reflect.DeepEqual(seq.Blocks, want) {
+		t.Errorf("blocks = %+v, want %+v", seq.Blocks, want)
+	}
+}
+
+func TestParseCRCCardInterface(t *testing.T) {
+	path := writeTempNamed(t, "crc-Store.md", "# Store\n**Requirements:** R1\n\n"+
+		"## Interface\n```go\n"+
+		"func (s *Store) GetByID(id string) (*Contact, error)\n"+
+		"type Filter struct{}\n"+
+		"```\n\n## Does\n- notAMethod(x): prose\n")
+	card, err := ParseCRCCard(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Signature{{Name: "GetByID", MinArgs: 1, MaxArgs: 1, Line: 6}}; !reflect.DeepEqual(card.Interface, want) {
+		t.Errorf("interface = %v, want %v", card.Interface, want)
+	}
+}
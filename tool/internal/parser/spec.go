@@ -0,0 +1,113 @@
+// CRC: crc-Parser.md | Seq: seq-parse.md | R122, R124, R126
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ErrNoHeading is returned by SpecFingerprint when the anchor names no heading.
+var ErrNoHeading = errors.New("no such heading")
+
+var specHeadingRe = regexp.MustCompile(`^(#{1,6})\s+(.+?)(?:\s+#+)?\s*$`)
+
+// ParseSpecHeadings lists the headings of a spec file outside code fences,
+// each with the anchor GitHub would generate for it (repeated anchors get
+// -1, -2, ... suffixes). R122
+func ParseSpecHeadings(path string) ([]SpecHeading, error) {
+	lines, err := ReadLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var headings []SpecHeading
+	seen := make(map[string]int)
+	inFence := false
+	for i, line := range lines {
+		lineNum := i + 1
+		if fenceRe.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		m := specHeadingRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		anchor := HeadingAnchor(m[2])
+		if n := seen[anchor]; n > 0 {
+			seen[anchor]++
+			anchor = fmt.Sprintf("%s-%d", anchor, n)
+		} else {
+			seen[anchor] = 1
+		}
+		headings = append(headings, SpecHeading{Title: m[2], Anchor: anchor, Level: len(m[1]), Line: lineNum})
+	}
+	return headings, nil
+}
+
+// HeadingAnchor converts heading text to its GitHub anchor: lowercase,
+// punctuation dropped, spaces turned into hyphens. R122
+func HeadingAnchor(title string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			sb.WriteRune(r)
+		case r == ' ':
+			sb.WriteByte('-')
+		}
+	}
+	return sb.String()
+}
+
+// SpecFingerprint hashes a spec file, or with an anchor just that heading's
+// section (up to the next heading of the same or a higher level). Trailing
+// whitespace and blank lines around the text do not change it. Returns the
+// first 12 hex digits of the SHA-256. R124
+func SpecFingerprint(path, anchor string) (string, error) {
+	lines, err := ReadLines(path)
+	if err != nil {
+		return "", err
+	}
+	if anchor != "" {
+		headings, err := ParseSpecHeadings(path)
+		if err != nil {
+			return "", err
+		}
+		start := slices.IndexFunc(headings, func(h SpecHeading) bool { return h.Anchor == anchor })
+		if start < 0 {
+			return "", fmt.Errorf("%s#%s: %w", path, anchor, ErrNoHeading)
+		}
+		end := len(lines)
+		for _, h := range headings[start+1:] {
+			if h.Level <= headings[start].Level {
+				end = h.Line - 1
+				break
+			}
+		}
+		lines = lines[headings[start].Line-1 : end]
+	}
+
+	return Fingerprint(strings.Join(lines, "\n")), nil
+}
+
+// Fingerprint returns the first 12 hex digits of the SHA-256 of text, after
+// dropping trailing whitespace on each line and blank lines around it. R124, R126
+func Fingerprint(text string) string {
+	var sb strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		sb.WriteString(strings.TrimRight(line, " \t"))
+		sb.WriteByte('\n')
+	}
+	sum := sha256.Sum256([]byte(strings.Trim(sb.String(), "\n")))
+	return hex.EncodeToString(sum[:])[:12]
+}
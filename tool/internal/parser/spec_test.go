@@ -0,0 +1,69 @@
+// CRC: crc-Parser.md | R121, R122, R124
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSpecHeadings(t *testing.T) {
+	path := writeTempNamed(t, "auth.md", "# Auth\n\n## Password Reset\n\n```\n## not a heading\n```\n\n### Login & Logout ###\n\n## Password Reset\n")
+	headings, err := ParseSpecHeadings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SpecHeading{
+		{Title: "Auth", Anchor: "auth", Level: 1, Line: 1},
+		{Title: "Password Reset", Anchor: "password-reset", Level: 2, Line: 3},
+		{Title: "Login & Logout", Anchor: "login--logout", Level: 3, Line: 9},
+		{Title: "Password Reset", Anchor: "password-reset-1", Level: 2, Line: 11},
+	}
+	if len(headings) != len(want) {
+		t.Fatalf("headings = %+v", headings)
+	}
+	for i := range want {
+		if headings[i] != want[i] {
+			t.Errorf("heading %d = %+v, want %+v", i, headings[i], want[i])
+		}
+	}
+}
+
+func TestParseRequirementsSourceAnchor(t *testing.T) {
+	path := writeTempNamed(t, "requirements.md", "## Feature: Auth\n**Source:** specs/auth.md#password-reset\n\n"+
+		"- **R1:** reset by mail\n"+
+		"- **R2:** [source: #login] log in\n"+
+		"- **R3:** [P1] [source: specs/session.md] expire sessions\n")
+	reqs, err := ParseRequirements(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"specs/auth.md#password-reset", "specs/auth.md#login", "specs/session.md"} {
+		if reqs[i].Source != want {
+			t.Errorf("R%d source = %q, want %q", i+1, reqs[i].Source, want)
+		}
+	}
+	if reqs[0].SourceFile() != "specs/auth.md" || reqs[0].SourceAnchor() != "password-reset" {
+		t.Errorf("R1 file, anchor = %q, %q", reqs[0].SourceFile(), reqs[0].SourceAnchor())
+	}
+	if reqs[2].Text != "expire sessions" || reqs[2].Priority != "P1" {
+		t.Errorf("R3 = %+v", reqs[2])
+	}
+}
+
+func TestSpecFingerprint(t *testing.T) {
+	path := writeTempNamed(t, "auth.md", "# Auth\n\n## Reset\nmail a link\n\n### Expiry\none hour\n\n## Login\nform\n")
+	section, err := SpecFingerprint(path, "reset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := writeTempNamed(t, "auth.md", "# Auth\n\n## Reset\nmail a link   \n\n### Expiry\none hour\n\n## Login\nform changed\n")
+	if got, _ := SpecFingerprint(edited, "reset"); got != section {
+		t.Errorf("edit outside the section changed its fingerprint: %s != %s", got, section)
+	}
+	if whole, _ := SpecFingerprint(edited, ""); whole == section {
+		t.Error("whole-file fingerprint equals the section's")
+	}
+	if _, err := SpecFingerprint(path, "nope"); !errors.Is(err, ErrNoHeading) {
+		t.Errorf("missing anchor error = %v", err)
+	}
+}
@@ -0,0 +1,331 @@
+// CRC: crc-Parser.md | Seq: seq-parse.md | R104, R105
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrNoScanner is returned by ParseCodeSymbols for languages it cannot scan.
+var ErrNoScanner = errors.New("no symbol scanner for file type")
+
+// ErrUnparsable is returned by ParseCodeSymbols for a readable file whose
+// source does not parse; its compiler reports the syntax error.
+var ErrUnparsable = errors.New("source does not parse")
+
+var (
+	jsFuncRe   = regexp.MustCompile(`^\s*export\s+(?:default\s+)?(?:async\s+)?function\s*\*?\s*([A-Za-z_$][\w$]*)\s*(?:<[^(]*>)?\s*\(`)
+	jsConstRe  = regexp.MustCompile(`^\s*export\s+(?:const|let)\s+([A-Za-z_$][\w$]*)\s*(?::[^=]+)?=\s*(?:async\s*)?(?:function\b[^(]*)?\(`)
+	jsClassRe  = regexp.MustCompile(`^\s*export\s+(?:default\s+)?(?:abstract\s+)?class\b`)
+	jsMethodRe = regexp.MustCompile(`^\s*((?:(?:public|private|protected|static|async|readonly|override|abstract|get|set)\s+)*)\*?([A-Za-z_$#][\w$]*)\s*\??\s*(?:<[^(]*>)?\s*\(`)
+	pyDefRe    = regexp.MustCompile(`^(\s*)(?:async\s+)?def\s+([A-Za-z_]\w*)\s*\(`)
+	pyClassRe  = regexp.MustCompile(`^(\s*)class\s+\w+`)
+)
+
+// scriptKeywords look like method declarations inside a class body but are
+// not. A constructor is reported as a method named "constructor", as CRC
+// Interface blocks declare it.
+var scriptKeywords = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true,
+	"catch": true, "return": true, "function": true, "super": true,
+}
+
+// ParseCodeSymbols returns the public functions and methods a code file
+// declares: exported funcs and exported methods of exported types for Go (via
+// go/parser), exported
+// functions and public methods of exported classes for TS/JS, and public module
+// functions and class methods for Python. Other file types return ErrNoScanner. R105
+func ParseCodeSymbols(path string) ([]Signature, error) {
+	lines, err := ReadLines(path)
+	if err != nil {
+		return nil, err
+	}
+	switch filepath.Ext(path) {
+	case ".go":
+		return goSymbols(path, lines)
+	case ".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs":
+		return scriptSymbols(lines), nil
+	case ".py":
+		return pythonSymbols(lines), nil
+	}
+	return nil, ErrNoScanner
+}
+
+func goSymbols(path string, lines []string) ([]Signature, error) {
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, path, strings.Join(lines, "\n"), goparser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnparsable, err)
+	}
+	var sigs []Signature
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || !fn.Name.IsExported() || (fn.Recv != nil && !ast.IsExported(receiverType(fn.Recv))) {
+			continue
+		}
+		sig := Signature{Name: fn.Name.Name, Line: fset.Position(fn.Pos()).Line}
+		for _, field := range fn.Type.Params.List {
+			n := max(len(field.Names), 1)
+			if _, variadic := field.Type.(*ast.Ellipsis); variadic {
+				sig.MinArgs += n - 1
+				sig.MaxArgs = -1
+				continue
+			}
+			sig.MinArgs += n
+			sig.MaxArgs += n
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs, nil
+}
+
+// receiverType returns the type name of a method receiver (T for *T or T[K]).
+func receiverType(recv *ast.FieldList) string {
+	if len(recv.List) == 0 {
+		return ""
+	}
+	expr := recv.List[0].Type
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.ParenExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
+func scriptSymbols(lines []string) []Signature {
+	var sigs []Signature
+	var classDepths []int // brace depth of each enclosing class body
+	pending := 0          // body depth of a class whose "{" has not been seen
+	depth := 0
+
+	for i, line := range lines {
+		code := stripScriptLiterals(line)
+		inClassBody := len(classDepths) > 0 && depth == classDepths[len(classDepths)-1]
+
+		switch {
+		case jsClassRe.MatchString(code):
+			pending = depth + 1
+		case inClassBody:
+			if m := jsMethodRe.FindStringSubmatchIndex(code); m != nil {
+				mods, name := code[m[2]:m[3]], code[m[4]:m[5]]
+				if !scriptKeywords[name] && !isPrivateName(name) &&
+					!strings.Contains(mods, "private") && !strings.Contains(mods, "protected") {
+					sigs = append(sigs, signatureAt(lines, i, name, m[1]-1))
+				}
+			}
+		default:
+			for _, re := range []*regexp.Regexp{jsFuncRe, jsConstRe} {
+				if m := re.FindStringSubmatchIndex(code); m != nil {
+					sigs = append(sigs, signatureAt(lines, i, code[m[2]:m[3]], m[1]-1))
+					break
+				}
+			}
+		}
+
+		depth += strings.Count(code, "{") - strings.Count(code, "}")
+		if pending > 0 && depth >= pending {
+			classDepths = append(classDepths, pending)
+			pending = 0
+		}
+		for len(classDepths) > 0 && depth < classDepths[len(classDepths)-1] {
+			classDepths = classDepths[:len(classDepths)-1]
+		}
+	}
+	return sigs
+}
+
+func pythonSymbols(lines []string) []Signature {
+	var sigs []Signature
+	type class struct {
+		indent, body int // body is -1 until the first body line is seen
+	}
+	var classes []class
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		for len(classes) > 0 && indent <= classes[len(classes)-1].indent {
+			classes = classes[:len(classes)-1]
+		}
+		if len(classes) > 0 && classes[len(classes)-1].body < 0 {
+			classes[len(classes)-1].body = indent
+		}
+
+		if m := pyClassRe.FindStringSubmatch(line); m != nil {
+			classes = append(classes, class{indent: indent, body: -1})
+			continue
+		}
+		m := pyDefRe.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		name := line[m[4]:m[5]]
+		public := !isPrivateName(name)
+		topLevel := len(classes) == 0 && indent == 0
+		method := len(classes) > 0 && indent == classes[len(classes)-1].body
+		if public && (topLevel || method) {
+			sigs = append(sigs, signatureAt(lines, i, name, m[1]-1))
+		}
+	}
+	return sigs
+}
+
+// interfaceSignature returns the function or method declared on line i of an
+// Interface block, or false if the line declares none. R104
+func interfaceSignature(lines []string, i int) (Signature, bool) {
+	for _, m := range declNameRe.FindAllStringSubmatchIndex(lines[i], -1) {
+		name := lines[i][m[2]:m[3]]
+		if !declKeywords[name] {
+			return signatureAt(lines, i, name, m[1]-1), true
+		}
+	}
+	return Signature{}, false
+}
+
+// signatureAt builds a Signature for name whose parameter list opens at
+// lines[i][open]; the list may continue onto following lines.
+func signatureAt(lines []string, i int, name string, open int) Signature {
+	minArgs, maxArgs := arity(paramList(lines, i, open))
+	return Signature{Name: name, MinArgs: minArgs, MaxArgs: maxArgs, Line: i + 1}
+}
+
+// paramList returns the text between the "(" at lines[i][open] and its
+// matching ")", joining up to 50 lines.
+func paramList(lines []string, i, open int) string {
+	var sb strings.Builder
+	depth := 0
+	text := lines[i][open:]
+	for n := 0; n < 50; n++ {
+		for _, r := range text {
+			switch r {
+			case '(':
+				depth++
+				if depth == 1 {
+					continue
+				}
+			case ')':
+				depth--
+				if depth == 0 {
+					return sb.String()
+				}
+			}
+			sb.WriteRune(r)
+		}
+		if i+n+1 >= len(lines) {
+			break
+		}
+		sb.WriteByte(' ')
+		text = lines[i+n+1]
+	}
+	return sb.String()
+}
+
+// arity counts required and accepted arguments of a parameter list. Optional
+// parameters (`x?: T`, `x = 1`) only raise the maximum; rest/variadic
+// parameters (`...xs`, `*args`, `**kw`) make it unbounded (-1); a leading
+// Python self/cls is not an argument.
+func arity(params string) (minArgs, maxArgs int) {
+	for i, p := range splitTopLevel(params) {
+		p = strings.TrimSpace(p)
+		switch {
+		case p == "" || p == "/" || p == "*":
+		case i == 0 && (p == "self" || p == "cls"):
+		case strings.Contains(p, "...") || strings.HasPrefix(p, "*"):
+			maxArgs = -1
+		case isOptionalParam(p):
+			if maxArgs >= 0 {
+				maxArgs++
+			}
+		default:
+			minArgs++
+			if maxArgs >= 0 {
+				maxArgs++
+			}
+		}
+	}
+	return minArgs, maxArgs
+}
+
+// isOptionalParam reports a TS optional (`x?: T`) or defaulted (`x = 1`) parameter.
+func isOptionalParam(p string) bool {
+	name, _, _ := strings.Cut(p, ":")
+	if strings.HasSuffix(strings.TrimSpace(name), "?") {
+		return true
+	}
+	p = strings.ReplaceAll(p, "=>", "")
+	p = strings.ReplaceAll(p, "==", "")
+	return strings.Contains(p, "=")
+}
+
+// splitTopLevel splits s on commas outside brackets, braces and generics.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(', '[', '{', '<':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case '>':
+			if i == 0 || s[i-1] != '=' {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// stripScriptLiterals blanks string contents and drops a trailing // comment
+// so braces inside them are not counted.
+func stripScriptLiterals(line string) string {
+	b := []byte(line)
+	var quote byte
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && i+1 < len(b) {
+				b[i], b[i+1] = ' ', ' '
+				i++
+			} else if c == quote {
+				quote = 0
+			} else {
+				b[i] = ' '
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '/' && i+1 < len(b) && b[i+1] == '/':
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
+
+func isPrivateName(name string) bool {
+	return strings.HasPrefix(name, "_") || strings.HasPrefix(name, "#")
+}
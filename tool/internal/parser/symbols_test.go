@@ -0,0 +1,69 @@
+// CRC: crc-Parser.md | R104, R105
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseCodeSymbols(t *testing.T) {
+	tests := []struct {
+		name, content string
+		want          []Signature
+	}{
+		{"store.ts", "import { x } from './x';\n" +
+			"export function makeStore<T>(a: string, b?: number): Store {\n  return new Store();\n}\n" +
+			"export class Store {\n" +
+			"  private items: Map<string, Item> = new Map();\n" +
+			"  constructor(private db: Db) {}\n" +
+			"  getFiltered(pred: (c: Item, i: number) => boolean): Item[] {\n" +
+			"    if (pred(x, 0)) { return []; }\n    return [];\n  }\n" +
+			"  private reindex(): void {}\n" +
+			"  static async load(\n    path: string,\n    ...opts: string[]\n  ): Promise<Store> { return s; }\n" +
+			"}\n" +
+			"function helper(a) {}\n",
+			[]Signature{
+				{Name: "makeStore", MinArgs: 1, MaxArgs: 2, Line: 2},
+				{Name: "constructor", MinArgs: 1, MaxArgs: 1, Line: 7},
+				{Name: "getFiltered", MinArgs: 1, MaxArgs: 1, Line: 8},
+				{Name: "load", MinArgs: 1, MaxArgs: -1, Line: 13},
+			}},
+		{"view.ts", "class Helper {\n  render(): void {}\n  update(a: number) {}\n}\n" +
+			"export default class View {\n  render(): void {}\n}\n",
+			[]Signature{
+				{Name: "render", Line: 6},
+			}},
+		{"store.py", "def make_store(path, mode='r'):\n    def inner(x):\n        pass\n\n" +
+			"class Store:\n    \"\"\"Doc.\"\"\"\n    def __init__(self, db):\n        pass\n\n" +
+			"    def get(self, key, *args, **kw):\n        pass\n\n    def _private(self):\n        pass\n\n" +
+			"def _hidden():\n    pass\n",
+			[]Signature{
+				{Name: "make_store", MinArgs: 1, MaxArgs: 2, Line: 1},
+				{Name: "get", MinArgs: 1, MaxArgs: -1, Line: 10},
+			}},
+		{"store.go", "package store\n\nfunc New(a, b int) *Store { return nil }\n\n" +
+			"func (s *Store) Add(items ...string) {}\n\nfunc (s *Store) lock() {}\n\n" +
+			"type cache[K comparable] struct{}\n\nfunc (c *cache[K]) Get(k K) {}\n\nfunc (c cache[K]) Len() int { return 0 }\n",
+			[]Signature{
+				{Name: "New", MinArgs: 2, MaxArgs: 2, Line: 3},
+				{Name: "Add", MinArgs: 0, MaxArgs: -1, Line: 5},
+			}},
+	}
+	for _, tt := range tests {
+		got, err := ParseCodeSymbols(writeTempNamed(t, tt.name, tt.content))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := ParseCodeSymbols(writeTempNamed(t, "main.lua", "function f() end\n")); err != ErrNoScanner {
+		t.Errorf("lua error = %v, want ErrNoScanner", err)
+	}
+	if _, err := ParseCodeSymbols(writeTempNamed(t, "broken.go", "package broken\n\nfunc F( {\n")); !errors.Is(err, ErrUnparsable) {
+		t.Errorf("syntax error = %v, want ErrUnparsable", err)
+	}
+}
@@ -0,0 +1,128 @@
+// CRC: crc-Parser.md | Seq: seq-parse.md | R107, R108
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	testHeadingRe = regexp.MustCompile(`^##\s+Test:\s*(.+?)\s*$`)
+	testSectionRe = regexp.MustCompile(`^##\s+(.+?)\s*$`)
+	testGroupRe   = regexp.MustCompile(`^\*\*([^*]+)\*\*\s*$`)
+	testItemRe    = regexp.MustCompile(`^\s*(?:[-*]|\d+\.)\s+(.+?)\s*$`)
+
+	goTestFuncRe  = regexp.MustCompile(`^func\s+(Test\w+)\s*\(`)
+	goSubtestRe   = regexp.MustCompile(`\bt\.Run\(\s*"([^"]+)"`)
+	jsTestRe      = regexp.MustCompile(`(?:^|[^.\w$])(?:it|test)(?:\.(?:only|skip))?\s*\(\s*(?:"([^"]*)"|'([^']*)'|` + "`([^`]*)`)")
+	pyTestRe      = regexp.MustCompile(`^\s*(?:async\s+)?def\s+(test_\w+)\s*\(`)
+	testFileRefRe = regexp.MustCompile(`\btest-[\w-]+\.md\b`)
+)
+
+// ParseTestDesign parses a test-*.md file into test cases. Two layouts are
+// accepted: one `## Test: Name` heading per case, or bullet/numbered items
+// grouped under `## Section` headings and `**Group**` lines. Cases in a
+// section whose title mentions "manual" are marked Manual. R107
+func ParseTestDesign(path string) ([]TestCase, error) {
+	lines, err := ReadLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	headings := false
+	for _, line := range lines {
+		if testHeadingRe.MatchString(line) {
+			headings = true
+			break
+		}
+	}
+
+	var cases []TestCase
+	section, group := "", ""
+	inFence := false
+	for i, line := range lines {
+		lineNum := i + 1
+		if fenceRe.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		if headings {
+			if m := testHeadingRe.FindStringSubmatch(line); m != nil {
+				cases = append(cases, TestCase{Name: m[1], Line: lineNum})
+			}
+			continue
+		}
+
+		if m := testSectionRe.FindStringSubmatch(line); m != nil {
+			section, group = m[1], ""
+			continue
+		}
+		if m := testGroupRe.FindStringSubmatch(line); m != nil {
+			group = strings.TrimSpace(m[1])
+			continue
+		}
+		if section == "" {
+			continue
+		}
+		if m := testItemRe.FindStringSubmatch(line); m != nil {
+			cases = append(cases, TestCase{
+				Name:    m[1],
+				Section: section,
+				Group:   group,
+				Line:    lineNum,
+				Manual:  strings.Contains(strings.ToLower(section), "manual"),
+			})
+		}
+	}
+	return cases, nil
+}
+
+// ParseTestNames scans a test file for test names: Go `func TestXxx` and
+// `t.Run("...")` subtests, JS/TS `it("...")` and `test("...")`, Python
+// `def test_...`, and `Test: <name>` comments written with the file's
+// comment pattern (a `Test: test-x.md` file reference is not a name). R108
+func ParseTestNames(path, commentPattern string) ([]TestName, error) {
+	lines, err := ReadLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var markerRe *regexp.Regexp
+	if commentPattern != "" {
+		markerRe, err = regexp.Compile(`^\s*` + commentPattern + `Test:\s*(.+?)\s*$`)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var names []TestName
+	for i, line := range lines {
+		lineNum := i + 1
+		switch {
+		case goTestFuncRe.MatchString(line):
+			names = append(names, TestName{Name: goTestFuncRe.FindStringSubmatch(line)[1], Line: lineNum})
+		case pyTestRe.MatchString(line):
+			names = append(names, TestName{Name: pyTestRe.FindStringSubmatch(line)[1], Line: lineNum})
+		case markerRe != nil && markerRe.MatchString(line):
+			if name := markerRe.FindStringSubmatch(line)[1]; !testFileRefRe.MatchString(name) {
+				names = append(names, TestName{Name: name, Line: lineNum})
+			}
+		default:
+			for _, re := range []*regexp.Regexp{goSubtestRe, jsTestRe} {
+				for _, m := range re.FindAllStringSubmatch(line, -1) {
+					for _, name := range m[1:] {
+						if name != "" {
+							names = append(names, TestName{Name: name, Line: lineNum})
+							break
+						}
+					}
+				}
+			}
+		}
+	}
+	return names, nil
+}
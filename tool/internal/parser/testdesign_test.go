@@ -0,0 +1,77 @@
+// CRC: crc-Parser.md | R107, R108
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTestDesign(t *testing.T) {
+	path := writeTempNamed(t, "test-contacts.md", "# Test Design: Contacts\n\n"+
+		"## ContactStore Tests\n\n"+
+		"**CRUD Operations**\n"+
+		"- add() creates contact with unique id\n"+
+		"- getById() returns correct contact\n\n"+
+		"## Integration Tests (Manual)\n\n"+
+		"**Add Flow**\n"+
+		"1. Click Add -> panel shows empty form\n")
+	cases, err := ParseTestDesign(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TestCase{
+		{Name: "add() creates contact with unique id", Section: "ContactStore Tests", Group: "CRUD Operations", Line: 6},
+		{Name: "getById() returns correct contact", Section: "ContactStore Tests", Group: "CRUD Operations", Line: 7},
+		{Name: "Click Add -> panel shows empty form", Section: "Integration Tests (Manual)", Group: "Add Flow", Line: 12, Manual: true},
+	}
+	if !reflect.DeepEqual(cases, want) {
+		t.Errorf("got %+v\nwant %+v", cases, want)
+	}
+
+	path = writeTempNamed(t, "test-Parser.md", "# Test Design: Parser\n\n"+
+		"## Test: ParseCRCCard_ValidCard\n**Input:**\n```markdown\n- not a case\n```\n"+
+		"## Test: ParseGaps_AllTypes\n- also not a case\n")
+	cases, err = ParseTestDesign(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []TestCase{{Name: "ParseCRCCard_ValidCard", Line: 3}, {Name: "ParseGaps_AllTypes", Line: 8}}
+	if !reflect.DeepEqual(cases, want) {
+		t.Errorf("got %+v\nwant %+v", cases, want)
+	}
+}
+
+func TestParseTestNames(t *testing.T) {
+	path := writeTempNamed(t, "store.test.ts", "// Test: test-contacts.md - CRUD\n"+
+		"// Test: add() creates contact with unique id\n"+
+		"describe('Store', () => {\n"+
+		"  it(\"doesn't lose data\", () => {});\n"+
+		"  test.only('getAll returns all', () => {});\n"+
+		"  expect(/^\\d+$/.test('42')).toBe(true); helper.it(\"not a test\");\n"+
+		"});\n")
+	names, err := ParseTestNames(path, `//\s*`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TestName{
+		{Name: "add() creates contact with unique id", Line: 2},
+		{Name: "doesn't lose data", Line: 4},
+		{Name: "getAll returns all", Line: 5},
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got %+v\nwant %+v", names, want)
+	}
+
+	path = writeTempNamed(t, "store_test.go", "package store\n\nfunc TestAdd(t *testing.T) {\n\tt.Run(\"empty store\", func(t *testing.T) {})\n}\n")
+	names, _ = ParseTestNames(path, `//\s*`)
+	want = []TestName{{Name: "TestAdd", Line: 3}, {Name: "empty store", Line: 4}}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got %+v\nwant %+v", names, want)
+	}
+
+	path = writeTempNamed(t, "test_store.py", "def test_add_creates_id():\n    pass\n")
+	names, _ = ParseTestNames(path, `#\s*`)
+	if want := []TestName{{Name: "test_add_creates_id", Line: 1}}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got %+v\nwant %+v", names, want)
+	}
+}
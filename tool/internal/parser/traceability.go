@@ -1,10 +1,8 @@
-// CRC: crc-Parser.md | Seq: seq-parse.md | R67, R71
+// CRC: crc-Parser.md | Seq: seq-parse.md | R67, R71, R91, R92, R94
 package parser
 
 import (
-	"bufio"
 	"fmt"
-	"os"
 	"regexp"
 	"strings"
 )
@@ -17,39 +15,63 @@ var reqRefRe = regexp.MustCompile(`R\d+`)
 // The commentCloser is the closing delimiter for block-comment languages (e.g., "}" for Pascal).
 // If empty, only the built-in closers (-->, */) are stripped.
 func ParseTraceability(path string, commentPattern string, commentCloser string) (Traceability, error) {
-	file, err := os.Open(path)
+	lines, err := ReadLines(path)
 	if err != nil {
 		return Traceability{}, err
 	}
-	defer file.Close()
 
-	if commentPattern == "" {
-		commentPattern = `(?://|--|#)\s*`
-	}
-	pattern := fmt.Sprintf(`%sCRC:\s*([^\|]+)(?:\|\s*Seq:\s*([^\|]+))?(.*)`, commentPattern)
-	traceRe, err := regexp.Compile(pattern)
+	traceRe, err := TraceabilityRegexp(commentPattern)
 	if err != nil {
-		return Traceability{}, fmt.Errorf("invalid comment pattern %q: %w", commentPattern, err)
+		return Traceability{}, err
 	}
 
 	trace := Traceability{}
-	scanner := bufio.NewScanner(file)
 
-	for scanner.Scan() {
-		line := scanner.Text()
+	for i, line := range lines {
+		lineNum := i + 1
 
 		if matches := traceRe.FindStringSubmatch(line); matches != nil {
-			trace.CRCRefs = append(trace.CRCRefs, splitRefs(matches[1], commentCloser)...)
+			comment := TraceComment{
+				CRCRefs:  splitRefs(matches[1], commentCloser),
+				Line:     lineNum,
+				Unclosed: !HasCloser(line, commentCloser),
+			}
 			if matches[2] != "" {
-				trace.SeqRefs = append(trace.SeqRefs, splitRefs(matches[2], commentCloser)...)
+				comment.SeqRefs = splitRefs(matches[2], commentCloser)
 			}
 			if matches[3] != "" {
-				trace.ReqRefs = append(trace.ReqRefs, extractReqRefs(matches[3], commentCloser)...)
+				comment.ReqRefs = extractReqRefs(matches[3], commentCloser)
 			}
+			trace.CRCRefs = append(trace.CRCRefs, comment.CRCRefs...)
+			trace.SeqRefs = append(trace.SeqRefs, comment.SeqRefs...)
+			trace.ReqRefs = append(trace.ReqRefs, comment.ReqRefs...)
+			trace.Comments = append(trace.Comments, comment)
 		}
 	}
 
-	return trace, scanner.Err()
+	return trace, nil
+}
+
+// TraceabilityRegexp compiles the traceability-comment regex for a comment
+// prefix pattern. Submatch 1 is the CRC section, 2 the optional Seq section and
+// 3 the remainder (inline Rn refs and any closer).
+func TraceabilityRegexp(commentPattern string) (*regexp.Regexp, error) {
+	if commentPattern == "" {
+		commentPattern = `(?://|--|#)\s*`
+	}
+	pattern := fmt.Sprintf(`%sCRC:\s*([^\|]+)(?:\|\s*Seq:\s*([^\|]+))?(.*)`, commentPattern)
+	traceRe, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid comment pattern %q: %w", commentPattern, err)
+	}
+	return traceRe, nil
+}
+
+// HasCloser reports whether a line ends with the comment closer. Lines always
+// count as closed when no closer is configured. R92
+func HasCloser(line, commentCloser string) bool {
+	closer := strings.TrimSpace(commentCloser)
+	return closer == "" || strings.HasSuffix(strings.TrimRight(line, " \t\r"), closer)
 }
 
 func extractReqRefs(s string, commentCloser string) []string {
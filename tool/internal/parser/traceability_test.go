@@ -1,4 +1,4 @@
-// CRC: crc-Parser.md | Seq: seq-parse.md | R67
+// CRC: crc-Parser.md | Seq: seq-parse.md | R67, R91, R92
 package parser
 
 import (
@@ -83,3 +83,37 @@ func TestParseTraceability_MultipleLines(t *testing.T) {
 		t.Errorf("ReqRefs = %v, want %v", trace.ReqRefs, want)
 	}
 }
+
+func TestParseTraceability_Comments(t *testing.T) {
+	content := "package db\n// CRC: crc-DB.md | R5\nfunc a() {}\n// CRC: crc-DB.md, crc-Log.md | Seq: seq-crud.md | R7\n"
+	path := writeTemp(t, content)
+	trace, err := ParseTraceability(path, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TraceComment{
+		{CRCRefs: []string{"crc-DB.md"}, ReqRefs: []string{"R5"}, Line: 2},
+		{CRCRefs: []string{"crc-DB.md", "crc-Log.md"}, SeqRefs: []string{"seq-crud.md"}, ReqRefs: []string{"R7"}, Line: 4},
+	}
+	if !reflect.DeepEqual(trace.Comments, want) {
+		t.Errorf("Comments = %+v, want %+v", trace.Comments, want)
+	}
+}
+
+func TestParseTraceability_Unclosed(t *testing.T) {
+	content := "<!-- CRC: crc-Page.md | R1 -->\n<!-- CRC: crc-Page.md | R2\n<p>swallowed</p>\n"
+	path := writeTemp(t, content)
+	trace, err := ParseTraceability(path, `<!--\s*`, " -->")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trace.Comments) != 2 || trace.Comments[0].Unclosed || !trace.Comments[1].Unclosed {
+		t.Errorf("Comments = %+v, want second comment unclosed", trace.Comments)
+	}
+	if !HasCloser("// CRC: crc-A.md", "") {
+		t.Error("lines without a configured closer must count as closed")
+	}
+	if !HasCloser("<!-- CRC: crc-A.md -->\r", " -->") || HasCloser("<!-- CRC: crc-A.md\r", " -->") {
+		t.Error("a CRLF line's closer is not recognized")
+	}
+}
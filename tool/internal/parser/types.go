@@ -1,29 +1,206 @@
-// CRC: crc-Parser.md
+// CRC: crc-Parser.md | R134, R135
 package parser
 
+import "strings"
+
 // Requirement represents a single requirement from requirements.md
 type Requirement struct {
-	ID       string // e.g., "R1"
-	Text     string
-	Source   string // spec file path
-	Inferred bool
-	Retired  bool // R77: marked with strikethrough/Retired prefix
-	Line     int
+	ID         string // e.g., "R1"
+	Text       string
+	Feature    string // name of the enclosing ## Feature: section (R134)
+	Source     string // spec file path, optionally with a #heading-anchor (R121)
+	Inferred   bool
+	Retired    bool     // R77: marked with strikethrough/Retired prefix
+	RetiredBy  string   // Tn gap from the "(Retired Tn — ...)" marker (R135)
+	ReplacedBy string   // Rn from the marker's "see Rn", "" for no replacement (R135)
+	Priority   string   // [P1] or [priority: high] (R117)
+	Status     string   // [status: deferred]; empty means open (R117)
+	Tags       []string // trailing #tags, without the "#" (R117)
+	Line       int
+}
+
+// SourceFile returns the spec file part of Source. R121
+func (r Requirement) SourceFile() string {
+	file, _, _ := strings.Cut(r.Source, "#")
+	return file
+}
+
+// SourceAnchor returns the heading anchor of Source without the "#", or "". R121
+func (r Requirement) SourceAnchor() string {
+	_, anchor, _ := strings.Cut(r.Source, "#")
+	return anchor
+}
+
+// FeatureSource is the **Source:** line of a requirements.md feature with
+// the spec fingerprint recorded on it, if any. R124
+type FeatureSource struct {
+	Feature     string
+	Source      string // spec path, optionally with a #heading-anchor
+	Fingerprint string // from a trailing <!-- spec-hash: ... --> comment
+	Line        int
+}
+
+// SpecHeading is a markdown heading of a spec file with its GitHub-style
+// anchor. R122
+type SpecHeading struct {
+	Title  string
+	Anchor string
+	Level  int
+	Line   int
+}
+
+// StatusOrOpen returns the requirement's status, "open" when none is given. R117
+func (r Requirement) StatusOrOpen() string {
+	if r.Status == "" {
+		return "open"
+	}
+	return r.Status
+}
+
+// Deferred reports a requirement whose status is "deferred"; deferred
+// requirements are not expected to be covered yet. R119
+func (r Requirement) Deferred() bool {
+	return strings.EqualFold(r.Status, "deferred")
 }
 
 // CRCCard represents a parsed CRC card
 type CRCCard struct {
-	Name         string
-	Requirements []string // e.g., ["R1", "R3", "R7"]
-	Sequences    []string // e.g., ["seq-login.md", "seq-auth.md"]
+	Name             string         // heading text without a "CRC: " prefix (R115)
+	Type             string         // **Type**: value, e.g., "Observable Data Store" (R115)
+	Requirements     []string       // e.g., ["R1", "R3", "R7"]
+	Knows            []string       // ## Knows items (R115)
+	Does             []string       // ## Does items (R115)
+	Responsibilities []string       // ## Responsibilities items (R115)
+	Sequences        []string       // e.g., ["seq-login.md", "seq-auth.md"]
+	Interface        []Signature    // declared in the fenced ## Interface block (R99, R104)
+	InterfaceSource  string         // the fenced ## Interface block's text (R115)
+	Collaborators    []Collaborator // ## Collaborators list (R100)
+	Sections         []CRCSection   // every ## section in file order (R115)
+	Path             string
+	ReqLine          int // line number of Requirements field
+}
+
+// CRCSection is a `## Title` section of a CRC card and the lines it spans,
+// from its heading to its last non-blank line. R115
+type CRCSection struct {
+	Title   string
+	Line    int
+	EndLine int
+}
+
+// Signature is a declared function or method and the number of arguments it
+// accepts. MaxArgs is -1 for rest/variadic parameters. R104
+type Signature struct {
+	Name    string
+	MinArgs int
+	MaxArgs int
+	Line    int
+}
+
+// TestCase is one designed test from a test-*.md file. R107
+type TestCase struct {
+	Name    string // e.g., "add() creates contact with unique id"
+	Section string // enclosing ## heading, e.g., "ContactStore Tests"
+	Group   string // enclosing **Group** line, e.g., "CRUD Operations"
+	Line    int
+	Manual  bool // section is a manual test plan, not expected in code
+}
+
+// TestName is a test found in a test code file. R108
+type TestName struct {
+	Name string // e.g., "TestParseSequence" or "add() creates contact with unique id"
+	Line int
+}
+
+// Manifest represents a parsed manifest-*.md file. R110
+type Manifest struct {
+	Path    string
+	Entries []ManifestEntry
+	Classes []string // mode classes noted as "(class: .dark)", without the dot
+}
+
+// ManifestEntry is one `- Key: Value` line of a manifest.
+type ManifestEntry struct {
+	Section string // e.g., "Theme"
+	Group   string // e.g., "Dark Mode"
+	Key     string // e.g., "Primary"
+	Value   string // e.g., "#0066cc"
+	Line    int
+}
+
+// UILayout represents a parsed ui-*.md file. R111
+type UILayout struct {
+	Path       string
+	Components []UIComponent
+	Selectors  []string // backticked `.class` / `#id` references
+}
+
+// UIComponent is a **Component** group in a layout's Components section.
+type UIComponent struct {
+	Name  string // e.g., "Contact List"
+	CRC   string // e.g., "crc-ContactListView.md", if named
+	Items []string
+	Line  int
+}
+
+// Markup holds what a CSS or HTML file defines and uses. R112
+type Markup struct {
+	Path    string
+	Classes []string
+	IDs     []string
+	Tags    []string
+	Colors  []ColorUse
+}
+
+// ColorUse is a color value written in a stylesheet or markup file.
+type ColorUse struct {
+	Value string // as written, e.g., "#0066CC"
+	Line  int
+}
+
+// Collaborator is one entry of a CRC card's Collaborators list, e.g.
+// "- Contact (stores)" or "- Parser: to parse design files". R100
+type Collaborator struct {
+	Name string // e.g., "Contact"
+	Note string // e.g., "stores"
+	Line int
+}
+
+// Sequence represents a parsed seq-*.md file. R96
+type Sequence struct {
 	Path         string
-	ReqLine      int // line number of Requirements field
+	Participants []string // in order of first appearance
+	Messages     []SeqMessage
+	Blocks       []SeqBlock
+}
+
+// SeqMessage is one `A -> B: text` line, a `A --> B: text` reply, or a
+// participant's self note (`A: text`, From == To).
+type SeqMessage struct {
+	From   string
+	To     string
+	Text   string
+	Method string // called method when Text starts with `name(`, e.g. "getFiltered"
+	Reply  bool
+	Line   int
+	Block  int // index into Sequence.Blocks of the innermost enclosing block, -1 if none
+}
+
+// SeqBlock is an alt/loop/opt/par block closed by `end`.
+type SeqBlock struct {
+	Kind    string // alt, loop, opt, par, critical, break
+	Label   string
+	Line    int
+	EndLine int   // 0 if the block is never closed
+	Else    []int // lines of else branches
+	Parent  int   // enclosing block index, -1 if none
 }
 
 // Artifact represents a design file with its associated code files
 type Artifact struct {
 	DesignFile string
 	CodeFiles  []CodeFile
+	Line       int // line of the design file entry
 }
 
 // CodeFile represents a code file listed in Artifacts
@@ -45,7 +222,17 @@ type Gap struct {
 
 // Traceability represents traceability comments found in a code file
 type Traceability struct {
-	CRCRefs []string
-	SeqRefs []string
-	ReqRefs []string
+	CRCRefs  []string
+	SeqRefs  []string
+	ReqRefs  []string
+	Comments []TraceComment // per-comment refs, in file order
+}
+
+// TraceComment is a single traceability comment line. R91
+type TraceComment struct {
+	CRCRefs  []string
+	SeqRefs  []string
+	ReqRefs  []string
+	Line     int
+	Unclosed bool // R92: a comment closer is configured but missing from the line
 }
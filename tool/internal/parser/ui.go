@@ -0,0 +1,272 @@
+// CRC: crc-Parser.md | Seq: seq-parse.md | R110, R111, R112
+package parser
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	uiSectionRe   = regexp.MustCompile(`^##\s+(.+?)\s*$`)
+	uiGroupRe     = regexp.MustCompile(`^\*\*([^*]+)\*\*\s*(.*)$`)
+	uiEntryRe     = regexp.MustCompile(`^\s*[-*]\s+([^:]+?):\s*(.+?)\s*$`)
+	uiItemRe      = regexp.MustCompile(`^\s*[-*]\s+(.+?)\s*$`)
+	uiClassNoteRe = regexp.MustCompile(`\(class:\s*\.([\w-]+)\)`)
+	uiCRCRefRe    = regexp.MustCompile(`\((crc-[\w-]+\.md)\)`)
+	uiSelectorRe  = regexp.MustCompile("`([.#][A-Za-z_][\\w-]*)`")
+	colorRe       = regexp.MustCompile(`#[0-9a-fA-F]{3,8}\b|(?:rgba?|hsla?)\([^)]*\)`)
+	cssSelectorRe = regexp.MustCompile(`([.#])([A-Za-z_][\w-]*)`)
+	htmlAttrRe    = regexp.MustCompile(`\b(class|id|style)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	htmlTagRe     = regexp.MustCompile(`<([A-Za-z][\w-]*)`)
+	cssCommentRe  = regexp.MustCompile(`/\*.*?\*/`)
+)
+
+// ParseManifest parses a manifest-*.md file: `- Key: Value` entries grouped
+// under `## Section` headings and `**Group**` lines, plus mode classes noted
+// as `(class: .dark)` after a group. R110
+func ParseManifest(path string) (Manifest, error) {
+	lines, err := ReadLines(path)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	m := Manifest{Path: path}
+	section, group := "", ""
+	inFence := false
+	for i, line := range lines {
+		lineNum := i + 1
+		if fenceRe.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if s := uiSectionRe.FindStringSubmatch(line); s != nil {
+			section, group = s[1], ""
+			continue
+		}
+		if g := uiGroupRe.FindStringSubmatch(line); g != nil {
+			group = strings.TrimSpace(g[1])
+			if c := uiClassNoteRe.FindStringSubmatch(g[2]); c != nil {
+				m.Classes = append(m.Classes, c[1])
+			}
+			continue
+		}
+		if e := uiEntryRe.FindStringSubmatch(line); e != nil {
+			m.Entries = append(m.Entries, ManifestEntry{
+				Section: section,
+				Group:   group,
+				Key:     strings.TrimSpace(e[1]),
+				Value:   e[2],
+				Line:    lineNum,
+			})
+		}
+	}
+	return m, nil
+}
+
+// Colors returns the manifest entries whose value is a color.
+func (m Manifest) Colors() []ManifestEntry {
+	var colors []ManifestEntry
+	for _, e := range m.Entries {
+		if colorRe.MatchString(e.Value) {
+			colors = append(colors, e)
+		}
+	}
+	return colors
+}
+
+// ParseUILayout parses a ui-*.md file: `**Component**` groups under the
+// `## Components` heading (optionally naming their CRC card, as in
+// `**Header** (crc-App.md)`) with their list items, and any backticked
+// `.class` or `#id` selectors anywhere outside code fences. R111
+func ParseUILayout(path string) (UILayout, error) {
+	lines, err := ReadLines(path)
+	if err != nil {
+		return UILayout{}, err
+	}
+
+	ui := UILayout{Path: path}
+	inComponents := false
+	inFence := false
+	for i, line := range lines {
+		lineNum := i + 1
+		if fenceRe.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		for _, s := range uiSelectorRe.FindAllStringSubmatch(line, -1) {
+			ui.Selectors = append(ui.Selectors, s[1])
+		}
+		if s := uiSectionRe.FindStringSubmatch(line); s != nil {
+			inComponents = strings.EqualFold(s[1], "Components")
+			continue
+		}
+		if !inComponents {
+			continue
+		}
+		if g := uiGroupRe.FindStringSubmatch(line); g != nil {
+			c := UIComponent{Name: strings.TrimSpace(g[1]), Line: lineNum}
+			if ref := uiCRCRefRe.FindStringSubmatch(g[2]); ref != nil {
+				c.CRC = ref[1]
+			}
+			ui.Components = append(ui.Components, c)
+			continue
+		}
+		if item := uiItemRe.FindStringSubmatch(line); item != nil && len(ui.Components) > 0 {
+			last := &ui.Components[len(ui.Components)-1]
+			last.Items = append(last.Items, item[1])
+		}
+	}
+	return ui, nil
+}
+
+// ParseMarkup collects the classes, ids, tags and color values a stylesheet
+// (.css) or HTML file (.html, .htm) defines or uses. Colors are hex and
+// rgb()/hsl() values in CSS declarations, style attributes and <style>
+// blocks. Other file types yield an empty Markup. R112
+func ParseMarkup(path string) (Markup, error) {
+	lines, err := ReadLines(path)
+	if err != nil {
+		return Markup{}, err
+	}
+	mk := Markup{Path: path}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".css":
+		scanCSS(&mk, lines, 1)
+	case ".html", ".htm":
+		scanHTML(&mk, lines)
+	}
+	return mk, nil
+}
+
+// scanCSS splits CSS text into selectors (text before "{") and declarations
+// (text before ";" or "}"), recording classes and ids from selectors and
+// colors from declarations. firstLine is the file line of lines[0].
+func scanCSS(mk *Markup, lines []string, firstLine int) {
+	var seg strings.Builder
+	segLine := firstLine
+	inComment := false
+	flush := func(selector bool) {
+		text := seg.String()
+		seg.Reset()
+		if selector {
+			if strings.HasPrefix(strings.TrimSpace(text), "@") {
+				return
+			}
+			for _, m := range cssSelectorRe.FindAllStringSubmatch(text, -1) {
+				if m[1] == "." {
+					mk.Classes = append(mk.Classes, m[2])
+				} else {
+					mk.IDs = append(mk.IDs, m[2])
+				}
+			}
+			return
+		}
+		for _, c := range colorRe.FindAllString(text, -1) {
+			mk.Colors = append(mk.Colors, ColorUse{Value: c, Line: segLine})
+		}
+	}
+
+	for i, line := range lines {
+		if inComment {
+			end := strings.Index(line, "*/")
+			if end < 0 {
+				continue
+			}
+			line, inComment = line[end+2:], false
+		}
+		line = cssCommentRe.ReplaceAllString(line, "")
+		if start := strings.Index(line, "/*"); start >= 0 {
+			line, inComment = line[:start], true
+		}
+		if strings.TrimSpace(seg.String()) == "" {
+			segLine = firstLine + i
+		}
+		for _, r := range line {
+			switch r {
+			case '{':
+				flush(true)
+			case ';', '}':
+				flush(false)
+			default:
+				seg.WriteRune(r)
+				continue
+			}
+			segLine = firstLine + i
+		}
+		seg.WriteByte('\n')
+	}
+	flush(false)
+}
+
+// scanHTML records tags, class and id attributes, colors in style
+// attributes, and the contents of <style> blocks.
+func scanHTML(mk *Markup, lines []string) {
+	styleStart := -1
+	for i, line := range lines {
+		lineNum := i + 1
+		lower := strings.ToLower(line)
+		if styleStart >= 0 {
+			if end := strings.Index(lower, "</style>"); end >= 0 {
+				scanCSS(mk, append(lines[styleStart:i:i], line[:end]), styleStart+1)
+				styleStart = -1
+			}
+			continue
+		}
+		if start := strings.Index(lower, "<style"); start >= 0 {
+			if open := strings.Index(line[start:], ">"); open >= 0 {
+				rest := line[start+open+1:]
+				if end := strings.Index(strings.ToLower(rest), "</style>"); end >= 0 {
+					scanCSS(mk, []string{rest[:end]}, lineNum)
+				} else {
+					styleStart = i + 1
+					scanCSS(mk, []string{rest}, lineNum)
+				}
+			}
+		}
+
+		for _, m := range htmlTagRe.FindAllStringSubmatch(line, -1) {
+			mk.Tags = append(mk.Tags, strings.ToLower(m[1]))
+		}
+		for _, m := range htmlAttrRe.FindAllStringSubmatch(line, -1) {
+			value := m[2] + m[3]
+			switch strings.ToLower(m[1]) {
+			case "class":
+				mk.Classes = append(mk.Classes, strings.Fields(value)...)
+			case "id":
+				mk.IDs = append(mk.IDs, strings.TrimSpace(value))
+			case "style":
+				for _, c := range colorRe.FindAllString(value, -1) {
+					mk.Colors = append(mk.Colors, ColorUse{Value: c, Line: lineNum})
+				}
+			}
+		}
+	}
+}
+
+// FindColors returns the hex and rgb()/hsl() color values in s.
+func FindColors(s string) []string {
+	return colorRe.FindAllString(s, -1)
+}
+
+// NormalizeColor lowercases a color and expands #rgb/#rgba shorthand, and
+// drops spaces inside rgb()/hsl(), so equal colors compare equal.
+func NormalizeColor(c string) string {
+	c = strings.ToLower(strings.ReplaceAll(c, " ", ""))
+	if strings.HasPrefix(c, "#") && (len(c) == 4 || len(c) == 5) {
+		var sb strings.Builder
+		sb.WriteByte('#')
+		for _, r := range c[1:] {
+			sb.WriteRune(r)
+			sb.WriteRune(r)
+		}
+		return sb.String()
+	}
+	return c
+}
@@ -0,0 +1,84 @@
+// CRC: crc-Parser.md | R110, R111, R112
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseManifest(t *testing.T) {
+	path := writeTempNamed(t, "manifest-ui.md", "# UI Manifest\n\n## Theme\n\n"+
+		"**Light Mode**\n- Primary: #0066cc\n\n"+
+		"**Dark Mode** (class: .dark)\n- Primary: #4DA6FF\n\n"+
+		"## Global Components\n\n**Buttons**\n- Padding: 8px 16px\n")
+	m, err := ParseManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m.Classes, []string{"dark"}) {
+		t.Errorf("classes = %v", m.Classes)
+	}
+	want := []ManifestEntry{
+		{Section: "Theme", Group: "Light Mode", Key: "Primary", Value: "#0066cc", Line: 6},
+		{Section: "Theme", Group: "Dark Mode", Key: "Primary", Value: "#4DA6FF", Line: 9},
+	}
+	if got := m.Colors(); !reflect.DeepEqual(got, want) {
+		t.Errorf("colors = %+v, want %+v", got, want)
+	}
+	if len(m.Entries) != 3 {
+		t.Errorf("entries = %+v", m.Entries)
+	}
+}
+
+func TestParseUILayout(t *testing.T) {
+	path := writeTempNamed(t, "ui-layout.md", "# UI Layout\n\n```\n**NotAComponent**\n```\n\n"+
+		"## Components\n\n**Header** (crc-App.md)\n- Search input uses `#search`\n\n"+
+		"**Contact List**\n- Each item: `.contact-item`\n\n## Styling\n\n**Fonts**\n")
+	ui, err := ParseUILayout(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []UIComponent{
+		{Name: "Header", CRC: "crc-App.md", Items: []string{"Search input uses `#search`"}, Line: 9},
+		{Name: "Contact List", Items: []string{"Each item: `.contact-item`"}, Line: 12},
+	}
+	if !reflect.DeepEqual(ui.Components, want) {
+		t.Errorf("components = %+v, want %+v", ui.Components, want)
+	}
+	if !reflect.DeepEqual(ui.Selectors, []string{"#search", ".contact-item"}) {
+		t.Errorf("selectors = %v", ui.Selectors)
+	}
+}
+
+func TestParseMarkup(t *testing.T) {
+	css := writeTempNamed(t, "styles.css", "/* colors: #999 */\n:root {\n  --primary: #0066cc;\n}\n"+
+		"body.dark, #app > .list-item:hover {\n  color: rgb(1, 2, 3);\n}\n@media (max-width: 600px) {\n  .narrow { border: 1px solid #FFF; }\n}\n")
+	mk, err := ParseMarkup(css)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(mk.Classes, []string{"dark", "list-item", "narrow"}) || !reflect.DeepEqual(mk.IDs, []string{"app"}) {
+		t.Errorf("classes = %v, ids = %v", mk.Classes, mk.IDs)
+	}
+	wantColors := []ColorUse{{Value: "#0066cc", Line: 3}, {Value: "rgb(1, 2, 3)", Line: 6}, {Value: "#FFF", Line: 9}}
+	if !reflect.DeepEqual(mk.Colors, wantColors) {
+		t.Errorf("colors = %+v, want %+v", mk.Colors, wantColors)
+	}
+
+	html := writeTempNamed(t, "index.html", "<html>\n<style>\n  .badge { color: #c33; }\n</style>\n"+
+		"<header class=\"header main\" id=\"top\" style=\"background: #eee\"></header>\n</html>\n")
+	mk, err = ParseMarkup(html)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(mk.Classes, []string{"badge", "header", "main"}) || !reflect.DeepEqual(mk.IDs, []string{"top"}) {
+		t.Errorf("classes = %v, ids = %v", mk.Classes, mk.IDs)
+	}
+	wantColors = []ColorUse{{Value: "#c33", Line: 3}, {Value: "#eee", Line: 5}}
+	if !reflect.DeepEqual(mk.Colors, wantColors) {
+		t.Errorf("colors = %+v, want %+v", mk.Colors, wantColors)
+	}
+	if NormalizeColor("#C33") != "#cc3333" || NormalizeColor("RGB(1, 2, 3)") != "rgb(1,2,3)" {
+		t.Error("NormalizeColor")
+	}
+}
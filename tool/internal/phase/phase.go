@@ -1,10 +1,11 @@
-// CRC: crc-Phase.md | Seq: seq-phase.md | R44, R45, R46, R47, R48, R49, R50, R76, R87
+// CRC: crc-Phase.md | Seq: seq-phase.md | R44, R45, R46, R47, R48, R49, R50, R76, R87, R89, R91, R92, R95, R98, R99, R101, R102, R106, R109, R113, R114, R118, R122, R124, R126, R137, R138
 package phase
 
 import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/zot/minispec/internal/project"
@@ -12,18 +13,21 @@ import (
 	"github.com/zot/minispec/internal/validate"
 )
 
-// Result is the output of a phase validation. Body is the issues-only block
-// (without trailing status line); Passed signals success.
+// Result is the output of a phase validation. Body is the issues: block
+// and any warnings: and info: blocks (without trailing status line); Passed
+// signals success.
 type Result struct {
-	Phase  string `json:"phase"`
-	Passed bool   `json:"passed"`
-	Body   string `json:"body,omitempty"`
+	Phase  string   `json:"phase"`
+	Passed bool     `json:"passed"`
+	Body   string   `json:"body,omitempty"`
+	Rules  []string `json:"rules,omitempty"` // IDs of the rules with findings (R137)
 }
 
 // Phase runs phase-specific validations
 type Phase struct {
 	Project *project.Project
 	Query   *query.Query
+	FailOn  validate.Severity // least severity that fails the phase, error when empty (R138)
 }
 
 // New creates a new Phase instance
@@ -36,7 +40,7 @@ func (ph *Phase) RunSpec() *Result {
 	specsDir := filepath.Join(ph.Project.RootPath, "specs")
 	entries, err := os.ReadDir(specsDir)
 	if err != nil {
-		return &Result{Phase: "spec", Body: fmt.Sprintf("  specs/ directory: %v\n", err)}
+		return &Result{Phase: "spec", Body: fmt.Sprintf("issues:\n  specs/ directory: %v\n", err)}
 	}
 
 	var issues []string
@@ -57,25 +61,39 @@ func (ph *Phase) RunSpec() *Result {
 
 	r := &Result{Phase: "spec", Passed: len(issues) == 0}
 	if !r.Passed {
-		r.Body = strings.Join(issues, "\n") + "\n"
+		r.Body = "issues:\n" + strings.Join(issues, "\n") + "\n"
 	}
 	return r
 }
 
-// runSubset runs full validation and emits only the issue categories listed.
-func (ph *Phase) runSubset(name string, categories []string) *Result {
+// runSubset runs only the listed rules and emits their issues. Rules turned
+// off in .minispec.yaml stay off. R137
+func (ph *Phase) runSubset(name string, rules []string) *Result {
+	failed := func(err error) *Result {
+		return &Result{Phase: name, Body: fmt.Sprintf("issues:\n  %v\n", err)}
+	}
 	v := validate.New(ph.Project)
-	full, err := v.Run()
+	reg, err := v.Registry()
 	if err != nil {
-		return &Result{Phase: name, Body: fmt.Sprintf("  %v\n", err)}
+		return failed(err)
 	}
-	filtered := filterResult(full, categories)
-	r := &Result{Phase: name, Passed: !filtered.HasIssues()}
-	if !r.Passed {
-		body := filtered.FormatText()
-		body = strings.TrimSuffix(body, "\nphase: validate FAILED\n")
-		body = strings.TrimPrefix(body, "issues:\n")
-		r.Body = body
+	for _, rule := range reg.Rules() {
+		if !slices.Contains(rules, rule.ID()) {
+			if err := reg.SetEnabled(rule.ID(), false); err != nil {
+				return failed(err)
+			}
+		}
+	}
+	filtered, err := v.RunRules(reg)
+	if err != nil {
+		return failed(err)
+	}
+	filtered.SetThresholds(validate.Thresholds{FailOn: ph.FailOn})
+	r := &Result{Phase: name, Passed: filtered.Passed, Body: filtered.FormatIssues()}
+	for _, is := range filtered.Issues {
+		if !slices.Contains(r.Rules, is.Rule) {
+			r.Rules = append(r.Rules, is.Rule)
+		}
 	}
 	return r
 }
@@ -83,68 +101,34 @@ func (ph *Phase) runSubset(name string, categories []string) *Result {
 // RunRequirements validates requirement-level issues (R45, R87).
 func (ph *Phase) RunRequirements() *Result {
 	return ph.runSubset("requirements",
-		[]string{"DuplicateReqs", "ReqNumberingGaps", "MissingSpecSources"})
+		[]string{"duplicate-requirements", "numbering-gaps", "missing-spec-sources", "missing-spec-anchors",
+			"stale-requirement-sources", "invalid-requirement-metadata"})
 }
 
 // RunDesign validates design-level issues (R46, R87).
 func (ph *Phase) RunDesign() *Result {
 	return ph.runSubset("design",
-		[]string{"UncoveredReqs", "UnknownCRCRefs", "UnlistedDesignFiles",
-			"MissingCRCSequences", "OrphanCRCNoReqField"})
+		[]string{"uncovered-requirements", "unknown-crc-refs", "unlisted-design-files",
+			"crc-sequences-not-found", "crcs-without-requirements-field", "read-errors",
+			"unknown-sequence-participants", "sequence-methods-not-in-interface",
+			"unknown-collaborators", "asymmetric-collaborators", "collaborators-in-no-sequence",
+			"requirements-changed-since-design"})
 }
 
 // RunImplementation validates code-level issues (R47, R87).
 func (ph *Phase) RunImplementation() *Result {
 	return ph.runSubset("implementation",
-		[]string{"MissingArtifacts", "MissingTraceability", "MissingDesignRefs", "MissingImplCoverage"})
+		[]string{"missing-artifacts", "missing-traceability", "missing-design-refs", "missing-impl-coverage",
+			"artifact-trace-mismatch", "requirement-refs-not-in-crc", "unclosed-trace-comments", "read-errors",
+			"interface-methods-not-in-code", "undeclared-public-methods", "interface-arity-mismatch",
+			"designed-tests-not-found", "tests-without-design",
+			"ui-elements-not-in-markup", "manifest-colors-not-in-stylesheets", "colors-not-in-manifest"})
 }
 
 // RunGaps validates Gaps section structure (R48, R76, R87).
 func (ph *Phase) RunGaps() *Result {
 	return ph.runSubset("gaps",
-		[]string{"DuplicateGapIDs", "CheckboxedPermanent"})
-}
-
-// filterResult returns a copy of r with only the listed categories preserved.
-func filterResult(r *validate.ValidationResult, keep []string) *validate.ValidationResult {
-	out := &validate.ValidationResult{
-		UnknownCRCRefs:      map[string][]string{},
-		MissingDesignRefs:   map[string][]string{},
-		MissingCRCSequences: map[string][]string{},
-	}
-	for _, k := range keep {
-		switch k {
-		case "UncoveredReqs":
-			out.UncoveredReqs = r.UncoveredReqs
-		case "MissingImplCoverage":
-			out.MissingImplCoverage = r.MissingImplCoverage
-		case "DuplicateReqs":
-			out.DuplicateReqs = r.DuplicateReqs
-		case "ReqNumberingGaps":
-			out.ReqNumberingGaps = r.ReqNumberingGaps
-		case "UnknownCRCRefs":
-			out.UnknownCRCRefs = r.UnknownCRCRefs
-		case "MissingArtifacts":
-			out.MissingArtifacts = r.MissingArtifacts
-		case "MissingTraceability":
-			out.MissingTraceability = r.MissingTraceability
-		case "MissingDesignRefs":
-			out.MissingDesignRefs = r.MissingDesignRefs
-		case "UnlistedDesignFiles":
-			out.UnlistedDesignFiles = r.UnlistedDesignFiles
-		case "MissingSpecSources":
-			out.MissingSpecSources = r.MissingSpecSources
-		case "MissingCRCSequences":
-			out.MissingCRCSequences = r.MissingCRCSequences
-		case "CheckboxedPermanent":
-			out.CheckboxedPermanent = r.CheckboxedPermanent
-		case "DuplicateGapIDs":
-			out.DuplicateGapIDs = r.DuplicateGapIDs
-		case "OrphanCRCNoReqField":
-			out.OrphanCRCNoReqField = r.OrphanCRCNoReqField
-		}
-	}
-	return out
+		[]string{"duplicate-gap-ids", "permanent-gaps-with-checkbox"})
 }
 
 // FormatText returns the phase output: issues block (if any) plus status line.
@@ -157,7 +141,6 @@ func (r *Result) FormatText() string {
 		return fmt.Sprintf("phase: %s %s\n", r.Phase, status)
 	}
 	var sb strings.Builder
-	sb.WriteString("issues:\n")
 	sb.WriteString(r.Body)
 	if !strings.HasSuffix(r.Body, "\n") {
 		sb.WriteString("\n")
@@ -1,10 +1,12 @@
-// CRC: crc-Project.md | Seq: seq-init.md
+// CRC: crc-Project.md | Seq: seq-init.md | R98, R118, R126, R137, R138
 package project
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"gopkg.in/yaml.v3"
 )
@@ -16,6 +18,20 @@ type Config struct {
 	CodeExtensions  []string          `yaml:"code_extensions"`
 	CommentPatterns map[string]string `yaml:"comment_patterns"`
 	CommentClosers  map[string]string `yaml:"comment_closers"`
+	Externals       []string          `yaml:"externals"`  // sequence participants that are not CRC cards (R98)
+	Priorities      []string          `yaml:"priorities"` // allowed requirement priorities; empty allows any (R118)
+	Statuses        []string          `yaml:"statuses"`   // allowed requirement statuses; empty allows any (R118)
+	Tags            []string          `yaml:"tags"`       // allowed requirement tags; empty allows any (R118)
+	Rules           map[string]string `yaml:"rules"`      // validation rule ID -> on, off or a severity (R137, R138)
+	Severities      []PathSeverity    `yaml:"severities"` // per path glob severity overrides, later entries win (R138)
+}
+
+// PathSeverity gives validation findings about files matching Path a
+// severity of their own, for the listed rules or all rules. R138
+type PathSeverity struct {
+	Path     string   `yaml:"path"` // glob; ** spans directories
+	Severity string   `yaml:"severity"`
+	Rules    []string `yaml:"rules"`
 }
 
 // Project represents a mini-spec project
@@ -63,6 +79,7 @@ func DefaultConfig() Config {
 		CodeExtensions:  []string{".go", ".ts", ".js", ".lua", ".py", ".c", ".h", ".cpp", ".sh"},
 		CommentPatterns: DefaultCommentPatterns(),
 		CommentClosers:  DefaultCommentClosers(),
+		Externals:       []string{"User"},
 	}
 }
 
@@ -121,6 +138,13 @@ func loadProject(rootPath string) (*Project, error) {
 		for ext, closer := range userConfig.CommentClosers {
 			config.CommentClosers[ext] = closer
 		}
+		// Externals add to the defaults
+		config.Externals = append(config.Externals, userConfig.Externals...)
+		config.Priorities = userConfig.Priorities
+		config.Statuses = userConfig.Statuses
+		config.Tags = userConfig.Tags
+		config.Rules = userConfig.Rules
+		config.Severities = userConfig.Severities
 	}
 
 	return &Project{
@@ -156,6 +180,27 @@ func (p *Project) GlobCRCCards() ([]string, error) {
 	return filepath.Glob(p.DesignPath("crc-*.md"))
 }
 
+// GlobSequences returns all sequence diagram files. R96
+func (p *Project) GlobSequences() ([]string, error) {
+	return filepath.Glob(p.DesignPath("seq-*.md"))
+}
+
+// IsExternal reports whether a sequence participant is a configured external
+// actor rather than a CRC card. R98
+func (p *Project) IsExternal(name string) bool {
+	return slices.Contains(p.Config.Externals, name)
+}
+
+// AllowedStatuses returns the requirement statuses validate accepts: the
+// configured statuses plus "open" and "deferred", or nil (any) when none are
+// configured. R118
+func (p *Project) AllowedStatuses() []string {
+	if len(p.Config.Statuses) == 0 {
+		return nil
+	}
+	return append([]string{"open", "deferred"}, p.Config.Statuses...)
+}
+
 // SpecsDir returns the absolute specs/ path. R79
 func (p *Project) SpecsDir() string {
 	return filepath.Join(p.RootPath, "specs")
@@ -182,3 +227,39 @@ func (p *Project) CommentPattern(ext string) string {
 func (p *Project) CommentCloser(ext string) string {
 	return p.Config.CommentClosers[ext]
 }
+
+// Acks records, per CRC card file, the fingerprint of each requirement's text
+// as it was when the card was last acknowledged (crc filename -> Rn -> hash). R126
+type Acks map[string]map[string]string
+
+// AcksPath returns the path of the acknowledgement sidecar file. R126
+func (p *Project) AcksPath() string {
+	return filepath.Join(p.RootPath, ".minispec-acks.yaml")
+}
+
+// LoadAcks reads the acknowledgement sidecar; a missing file yields empty Acks. R126
+func (p *Project) LoadAcks() (Acks, error) {
+	acks := Acks{}
+	data, err := os.ReadFile(p.AcksPath())
+	if os.IsNotExist(err) {
+		return acks, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &acks); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", filepath.Base(p.AcksPath()), err)
+	}
+	return acks, nil
+}
+
+// SaveAcks writes the acknowledgement sidecar. R126
+func (p *Project) SaveAcks(acks Acks) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(acks); err != nil {
+		return err
+	}
+	return os.WriteFile(p.AcksPath(), buf.Bytes(), 0644)
+}
@@ -0,0 +1,65 @@
+// CRC: crc-Query.md | Seq: seq-query.md | R134
+package query
+
+import (
+	"github.com/zot/minispec/internal/parser"
+)
+
+// FeatureSummary is one `## Feature:` section of requirements.md. R134
+type FeatureSummary struct {
+	Name         string
+	Source       string   // the feature's **Source:** spec, "" if none
+	Requirements []string // Rn in file order, retired ones included
+	Stats        RequirementStats
+}
+
+// Features lists the requirements.md features in file order with their
+// source spec, requirements and design and implementation coverage.
+// Requirements before the first feature heading form a feature with no
+// name. R134
+func (q *Query) Features() ([]FeatureSummary, error) {
+	reqs, err := q.Requirements()
+	if err != nil {
+		return nil, err
+	}
+	sources, err := parser.ParseFeatureSources(q.Project.RequirementsPath())
+	if err != nil {
+		return nil, err
+	}
+	stats, err := q.Stats()
+	if err != nil {
+		return nil, err
+	}
+
+	// Order features by their first Source line or requirement.
+	var features []FeatureSummary
+	index := make(map[string]int)
+	feature := func(name string) *FeatureSummary {
+		i, ok := index[name]
+		if !ok {
+			i = len(features)
+			index[name] = i
+			features = append(features, FeatureSummary{Name: name})
+		}
+		return &features[i]
+	}
+	si := 0
+	for _, r := range reqs {
+		for ; si < len(sources) && sources[si].Line < r.Line; si++ {
+			if f := feature(sources[si].Feature); f.Source == "" {
+				f.Source = sources[si].Source
+			}
+		}
+		f := feature(r.Feature)
+		f.Requirements = append(f.Requirements, r.ID)
+	}
+	for ; si < len(sources); si++ {
+		if f := feature(sources[si].Feature); f.Source == "" {
+			f.Source = sources[si].Source
+		}
+	}
+	for _, s := range stats.Features {
+		features[index[s.Name]].Stats = s
+	}
+	return features, nil
+}
@@ -0,0 +1,227 @@
+// CRC: crc-Query.md | Seq: seq-query.md | R128, R129, R130
+package query
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/zot/minispec/internal/parser"
+)
+
+// GraphNode is a spec, requirement, design file or code file of the
+// traceability graph. IDs are "spec:<path>", "req:<Rn>", "design:<file>"
+// and "code:<path>". R129
+type GraphNode struct {
+	ID        string
+	Kind      string // spec, requirement, crc, sequence, design or code
+	Label     string
+	Retired   bool // retired requirement
+	Uncovered bool // requirement no CRC card lists
+}
+
+// GraphEdge is a traceability link in its downstream direction. Kind is
+// source (spec -> Rn), requirements (Rn -> CRC), sequences (CRC -> seq),
+// artifacts (design -> code) or trace (design or Rn -> code, from a
+// traceability comment). R129
+type GraphEdge struct {
+	From      string
+	To        string
+	Kind      string
+	Unchecked bool // artifacts edge from an unchecked Artifacts line
+}
+
+// TraceGraph is the project's traceability graph. Nodes are sorted by ID;
+// edges appear once per (From, To, Kind).
+type TraceGraph struct {
+	Nodes []GraphNode
+	Edges []GraphEdge
+}
+
+// Node returns the node with the given ID.
+func (g *TraceGraph) Node(id string) (GraphNode, bool) {
+	i, found := slices.BinarySearchFunc(g.Nodes, id, func(n GraphNode, id string) int { return strings.Compare(n.ID, id) })
+	if !found {
+		return GraphNode{}, false
+	}
+	return g.Nodes[i], true
+}
+
+// graphBuilder accumulates nodes and edges without duplicates.
+type graphBuilder struct {
+	nodes map[string]*GraphNode
+	edges map[GraphEdge]bool
+	order []GraphEdge
+}
+
+func (b *graphBuilder) node(id string) *GraphNode {
+	if n, ok := b.nodes[id]; ok {
+		return n
+	}
+	kind, label, _ := strings.Cut(id, ":")
+	switch {
+	case kind == "req":
+		kind = "requirement"
+	case kind == "design" && strings.HasPrefix(label, "crc-"):
+		kind = "crc"
+	case kind == "design" && strings.HasPrefix(label, "seq-"):
+		kind = "sequence"
+	}
+	n := &GraphNode{ID: id, Kind: kind, Label: label}
+	b.nodes[id] = n
+	return n
+}
+
+func (b *graphBuilder) link(from, to, kind string, unchecked bool) {
+	b.node(from)
+	b.node(to)
+	e := GraphEdge{From: from, To: to, Kind: kind, Unchecked: unchecked}
+	if !b.edges[e] {
+		b.edges[e] = true
+		b.order = append(b.order, e)
+	}
+}
+
+// Graph builds the traceability graph: spec -> Rn via Source, Rn -> CRC via
+// Requirements, CRC -> sequence via Sequences, design -> code via Artifacts,
+// and design or Rn -> code via traceability comments. Requirements no CRC
+// card lists are marked uncovered (retired and deferred ones excepted). R129
+func (q *Query) Graph() (*TraceGraph, error) {
+	b := &graphBuilder{nodes: make(map[string]*GraphNode), edges: make(map[GraphEdge]bool)}
+
+	reqs, err := q.Requirements()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reqs {
+		n := b.node("req:" + r.ID)
+		n.Retired = r.Retired
+		n.Uncovered = !r.Retired && !r.Deferred()
+		if r.Source != "" {
+			b.link("spec:"+r.SourceFile(), n.ID, "source", false)
+		}
+	}
+
+	files, err := q.Project.GlobCRCCards()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range files {
+		card, err := parser.ParseCRCCard(path)
+		if err != nil {
+			continue
+		}
+		name := "design:" + filepath.Base(path)
+		b.node(name)
+		for _, ref := range card.Requirements {
+			b.link("req:"+ref, name, "requirements", false)
+			b.node("req:" + ref).Uncovered = false
+		}
+		for _, seq := range card.Sequences {
+			b.link(name, "design:"+seq, "sequences", false)
+		}
+	}
+
+	artifacts, err := q.Artifacts()
+	if err != nil {
+		return nil, err
+	}
+	scanned := make(map[string]bool)
+	for _, art := range artifacts {
+		for _, cf := range art.CodeFiles {
+			code := "code:" + cf.Path
+			b.link("design:"+art.DesignFile, code, "artifacts", !cf.Checked)
+			if scanned[cf.Path] {
+				continue
+			}
+			scanned[cf.Path] = true
+			trace, err := q.Traceability(filepath.Join(q.Project.RootPath, cf.Path))
+			if err != nil {
+				continue
+			}
+			for _, ref := range trace.CRCRefs {
+				b.link("design:"+ref, code, "trace", false)
+			}
+			for _, ref := range trace.SeqRefs {
+				b.link("design:"+ref, code, "trace", false)
+			}
+			for _, ref := range trace.ReqRefs {
+				b.link("req:"+ref, code, "trace", false)
+			}
+		}
+	}
+
+	g := &TraceGraph{Edges: b.order}
+	for _, n := range b.nodes {
+		g.Nodes = append(g.Nodes, *n)
+	}
+	slices.SortFunc(g.Nodes, func(a, b GraphNode) int { return strings.Compare(a.ID, b.ID) })
+	return g, nil
+}
+
+// NodeID names the graph node for a target: an Rn, a file in the design
+// directory (by filename), or a spec or code path relative to the project
+// root. R128, R129
+func (q *Query) NodeID(target string) string {
+	if n, err := strconv.Atoi(strings.TrimPrefix(target, "R")); err == nil && n > 0 && strings.HasPrefix(target, "R") {
+		return "req:" + target
+	}
+	path := target
+	if abs, err := filepath.Abs(target); err == nil {
+		if rel, err := filepath.Rel(q.Project.DesignDir, abs); err == nil && !strings.HasPrefix(rel, "..") {
+			return "design:" + rel
+		}
+		if rel, err := filepath.Rel(q.Project.RootPath, abs); err == nil && !strings.HasPrefix(rel, "..") {
+			path = filepath.ToSlash(rel)
+		}
+	}
+	if strings.HasPrefix(path, "specs/") {
+		return "spec:" + path
+	}
+	if !strings.Contains(path, "/") && strings.HasSuffix(path, ".md") {
+		return "design:" + path
+	}
+	return "code:" + path
+}
+
+// Focus returns the part of the graph within depth links of the node id,
+// following edges in either direction. R130
+func (g *TraceGraph) Focus(id string, depth int) (*TraceGraph, error) {
+	if _, ok := g.Node(id); !ok {
+		return nil, fmt.Errorf("%s is not part of the traceability graph", strings.SplitN(id, ":", 2)[1])
+	}
+	adjacent := make(map[string][]string)
+	for _, e := range g.Edges {
+		adjacent[e.From] = append(adjacent[e.From], e.To)
+		adjacent[e.To] = append(adjacent[e.To], e.From)
+	}
+	keep := map[string]bool{id: true}
+	frontier := []string{id}
+	for i := 0; i < depth; i++ {
+		var next []string
+		for _, node := range frontier {
+			for _, n := range adjacent[node] {
+				if !keep[n] {
+					keep[n] = true
+					next = append(next, n)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	focused := &TraceGraph{}
+	for _, n := range g.Nodes {
+		if keep[n.ID] {
+			focused.Nodes = append(focused.Nodes, n)
+		}
+	}
+	for _, e := range g.Edges {
+		if keep[e.From] && keep[e.To] {
+			focused.Edges = append(focused.Edges, e)
+		}
+	}
+	return focused, nil
+}
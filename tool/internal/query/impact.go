@@ -0,0 +1,112 @@
+// CRC: crc-Query.md | Seq: seq-query.md | R128
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ImpactResult lists what a change to Target may affect: everything
+// downstream of it (spec -> requirements -> design -> code) and everything
+// upstream of it (code -> design -> requirements -> spec). R128
+type ImpactResult struct {
+	Target       string
+	Specs        []string // spec paths relative to the project root
+	Requirements []string // Rn, in numeric order
+	DesignFiles  []string // design filenames
+	CodeFiles    []string // code paths as listed in Artifacts
+}
+
+// walk returns every node reachable from the start nodes along edges.
+func walk(edges map[string][]string, start ...string) []string {
+	seen := make(map[string]bool)
+	for _, s := range start {
+		seen[s] = true
+	}
+	queue := slices.Clone(start)
+	var found []string
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range edges[node] {
+			if !seen[next] {
+				seen[next] = true
+				found = append(found, next)
+				queue = append(queue, next)
+			}
+		}
+	}
+	return found
+}
+
+// Impact walks the traceability graph downstream and upstream from a spec,
+// design or code file, or an Rn, without turning around; requirements.md
+// starts from every requirement. Retired requirements are not followed. R128
+func (q *Query) Impact(target string) (*ImpactResult, error) {
+	g, err := q.Graph()
+	if err != nil {
+		return nil, err
+	}
+	down := make(map[string][]string)
+	up := make(map[string][]string)
+	for _, e := range g.Edges {
+		if n, _ := g.Node(e.From); n.Retired {
+			continue
+		}
+		if n, _ := g.Node(e.To); n.Retired {
+			continue
+		}
+		down[e.From] = append(down[e.From], e.To)
+		up[e.To] = append(up[e.To], e.From)
+	}
+
+	var start []string
+	if id := q.NodeID(target); id == "design:requirements.md" {
+		for _, n := range g.Nodes {
+			if n.Kind == "requirement" && !n.Retired {
+				start = append(start, n.ID)
+			}
+		}
+	} else if _, ok := g.Node(id); ok {
+		start = []string{id}
+	} else {
+		return nil, fmt.Errorf("%s is not part of the traceability graph", target)
+	}
+
+	result := &ImpactResult{Target: target}
+	found := append(walk(down, start...), walk(up, start...)...)
+	if len(start) > 1 {
+		found = append(found, start...)
+	}
+	for _, node := range found {
+		kind, name, _ := strings.Cut(node, ":")
+		switch kind {
+		case "spec":
+			result.Specs = append(result.Specs, name)
+		case "req":
+			result.Requirements = append(result.Requirements, name)
+		case "design":
+			result.DesignFiles = append(result.DesignFiles, name)
+		case "code":
+			result.CodeFiles = append(result.CodeFiles, name)
+		}
+	}
+	for _, list := range []*[]string{&result.Specs, &result.DesignFiles, &result.CodeFiles} {
+		slices.Sort(*list)
+		*list = slices.Compact(*list)
+	}
+	result.Requirements = sortReqIDs(result.Requirements)
+	return result, nil
+}
+
+// sortReqIDs sorts and deduplicates Rn IDs numerically.
+func sortReqIDs(ids []string) []string {
+	num := func(id string) int {
+		n, _ := strconv.Atoi(strings.TrimPrefix(id, "R"))
+		return n
+	}
+	slices.SortFunc(ids, func(a, b string) int { return num(a) - num(b) })
+	return slices.Compact(ids)
+}
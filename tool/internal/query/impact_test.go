@@ -0,0 +1,126 @@
+// CRC: crc-Query.md | R128
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+// traceProject is a small project with every kind of traceability link: two
+// features with a retired (R3), inferred (R2), deferred (R4), approved (R6)
+// and uncovered (R7) requirement, two CRC cards and an unreadable one, two
+// sequences, code files with traceability comments, gaps and specs.
+func traceProject(t *testing.T) *Query {
+	t.Helper()
+	return testProject(t, map[string]string{
+		"design/requirements.md": `# Requirements
+
+## Feature: Contacts
+**Source:** specs/contacts.md
+
+- **R1:** Store contacts by id
+- **R2:** (inferred) Search contacts by name
+- **~~R3:~~** (Retired T1 — see R2) Filter contacts by name
+- **R4:** [status: deferred] Sync contacts to the server
+
+## Feature: Export
+**Source:** specs/export.md#csv
+
+- **R5:** Export contacts as CSV
+- **R6:** Print contacts
+- **R7:** Email contacts
+`,
+		"design/crc-Store.md": `# CRC: Store
+**Requirements:** R1, R2, R3
+
+## Knows
+- contacts by id
+
+## Does
+- search contacts by name
+
+## Sequences
+- seq-save.md
+`,
+		"design/crc-Exporter.md": `# CRC: Exporter
+**Requirements:** R5
+
+## Responsibilities
+- Write contacts as CSV
+`,
+		"design/crc-Broken.md": "\x00\x00# CRC: Broken\n**Requirements:** R7\n",
+		"design/seq-save.md":   "# Sequence: Save\n\n```\nApp -> Store: add(contact)\nStore: persist contacts\n```\n",
+		"design/seq-export.md": "# Sequence: Export\n\nCovers R5.\n\n```\nApp -> Exporter: toCSV(contacts)\n```\n",
+		"design/design.md": `# Design
+
+## Artifacts
+- [x] crc-Store.md → ` + "`src/store.ts`" + `
+- [ ] crc-Exporter.md → ` + "`src/export.ts`" + `
+- [x] seq-save.md → ` + "`src/store.ts`" + `
+
+## Gaps
+- [ ] D1: CSV export ignores R5 quoting
+- [x] D2: Store keeps deleted contacts
+- A1: R6 printing is approved as out of scope
+- T1: R3 retired by R2 (merged into search)
+`,
+		"src/store.ts":      "// CRC: crc-Store.md | Seq: seq-save.md | R1, R3\nexport class Store {}\n",
+		"src/export.ts":     "// CRC: crc-Exporter.md | R5\nexport function toCSV() {}\n",
+		"specs/contacts.md": "# Contacts\n\n## Search\n",
+		"specs/export.md":   "# Export\n\n## CSV\n\nComma separated.\n",
+	})
+}
+
+func TestImpact(t *testing.T) {
+	q := traceProject(t)
+	tests := []struct {
+		target string
+		want   ImpactResult
+	}{
+		{"specs/contacts.md", ImpactResult{
+			Requirements: []string{"R1", "R2", "R4"},
+			DesignFiles:  []string{"crc-Store.md", "seq-save.md"},
+			CodeFiles:    []string{"src/store.ts"},
+		}},
+		// upstream stops at the retired R3; downstream reaches the sequence
+		{"crc-Store.md", ImpactResult{
+			Specs:        []string{"specs/contacts.md"},
+			Requirements: []string{"R1", "R2"},
+			DesignFiles:  []string{"seq-save.md"},
+			CodeFiles:    []string{"src/store.ts"},
+		}},
+		{"src/store.ts", ImpactResult{
+			Specs:        []string{"specs/contacts.md"},
+			Requirements: []string{"R1", "R2"},
+			DesignFiles:  []string{"crc-Store.md", "seq-save.md"},
+		}},
+		{"R5", ImpactResult{
+			Specs:       []string{"specs/export.md"},
+			DesignFiles: []string{"crc-Exporter.md"},
+			CodeFiles:   []string{"src/export.ts"},
+		}},
+		// every live requirement, itself included
+		{"requirements.md", ImpactResult{
+			Specs:        []string{"specs/contacts.md", "specs/export.md"},
+			Requirements: []string{"R1", "R2", "R4", "R5", "R6", "R7"},
+			DesignFiles:  []string{"crc-Exporter.md", "crc-Store.md", "seq-save.md"},
+			CodeFiles:    []string{"src/export.ts", "src/store.ts"},
+		}},
+		// a retired requirement is in the graph but leads nowhere
+		{"R3", ImpactResult{}},
+	}
+	for _, tt := range tests {
+		got, err := q.Impact(tt.target)
+		if err != nil {
+			t.Fatalf("Impact(%q): %v", tt.target, err)
+		}
+		tt.want.Target = tt.target
+		if !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("Impact(%q) =\n %+v\nwant\n %+v", tt.target, *got, tt.want)
+		}
+	}
+
+	if _, err := q.Impact("R99"); err == nil {
+		t.Error("Impact(R99) accepted an unknown requirement")
+	}
+}
@@ -0,0 +1,141 @@
+// CRC: crc-Query.md | Seq: seq-query.md | R136
+package query
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/zot/minispec/internal/parser"
+)
+
+// MatrixRow is one requirement's row of the traceability matrix. R136
+type MatrixRow struct {
+	Requirement      string
+	Text             string
+	Feature          string
+	Status           string // retired, or the requirement's status (open when none)
+	Source           string
+	CRCCards         []string // crc filenames listing the requirement
+	Sequences        []string // sequences those cards list
+	CodeFiles        []string // code files whose traceability comments list the requirement
+	TestDesigns      []string // test-*.md files naming one of the cards
+	ArtifactsChecked int      // checked code files on the cards' Artifacts lines
+	ArtifactsTotal   int
+	Gaps             []string // gaps mentioning the requirement, as "D3 open"
+}
+
+// MatrixOptions selects the matrix rows: retired requirements are left out
+// unless Retired is set, and Feature restricts rows to one feature. R136
+type MatrixOptions struct {
+	Retired bool
+	Feature string
+}
+
+// Matrix builds one row per requirement from Coverage, TraceabilityAll,
+// Gaps, the CRC cards' Sequences, design.md Artifacts and the test
+// designs. R136
+func (q *Query) Matrix(opts MatrixOptions) ([]MatrixRow, error) {
+	reqs, err := q.Requirements()
+	if err != nil {
+		return nil, err
+	}
+	cov, err := q.Coverage()
+	if err != nil {
+		return nil, err
+	}
+	traces, err := q.TraceabilityAll()
+	if err != nil {
+		return nil, err
+	}
+	gaps, err := q.Gaps()
+	if err != nil {
+		return nil, err
+	}
+	artifacts, err := q.Artifacts()
+	if err != nil {
+		return nil, err
+	}
+
+	sequences := make(map[string][]string) // crc filename -> sequences
+	for path := range cov.CRCCards {
+		if card, err := parser.ParseCRCCard(path); err == nil {
+			sequences[filepath.Base(path)] = card.Sequences
+		}
+	}
+	checked := make(map[string][2]int) // design file -> checked, total
+	for _, art := range artifacts {
+		counts := checked[art.DesignFile]
+		for _, cf := range art.CodeFiles {
+			if cf.Checked {
+				counts[0]++
+			}
+			counts[1]++
+		}
+		checked[art.DesignFile] = counts
+	}
+	code := make(map[string][]string) // Rn -> code files
+	for path, trace := range traces {
+		for _, ref := range trace.ReqRefs {
+			code[ref] = append(code[ref], path)
+		}
+	}
+	testDesigns := make(map[string]string) // test design filename -> text
+	if files, err := filepath.Glob(q.Project.DesignPath("test-*.md")); err == nil {
+		for _, path := range files {
+			if lines, err := parser.ReadLines(path); err == nil {
+				testDesigns[filepath.Base(path)] = strings.Join(lines, "\n")
+			}
+		}
+	}
+
+	var rows []MatrixRow
+	for _, r := range reqs {
+		if (r.Retired && !opts.Retired) || (opts.Feature != "" && r.Feature != opts.Feature) {
+			continue
+		}
+		row := MatrixRow{Requirement: r.ID, Text: r.Text, Feature: r.Feature, Status: r.StatusOrOpen(), Source: r.Source}
+		if r.Retired {
+			row.Status = "retired"
+		}
+		for _, path := range cov.Coverage[r.ID] {
+			card := filepath.Base(path)
+			row.CRCCards = append(row.CRCCards, card)
+			row.Sequences = append(row.Sequences, sequences[card]...)
+			counts := checked[card]
+			row.ArtifactsChecked += counts[0]
+			row.ArtifactsTotal += counts[1]
+			for name, text := range testDesigns {
+				if strings.Contains(text, card) {
+					row.TestDesigns = append(row.TestDesigns, name)
+				}
+			}
+		}
+		row.CodeFiles = code[r.ID]
+		for _, g := range gaps {
+			if mentionsReq(g.Description, r.ID) {
+				row.Gaps = append(row.Gaps, g.ID+" "+gapState(g))
+			}
+		}
+		for _, list := range []*[]string{&row.CRCCards, &row.Sequences, &row.CodeFiles, &row.TestDesigns} {
+			slices.Sort(*list)
+			*list = slices.Compact(*list)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// gapState names a gap's state: approved (A), retirement (T), resolved or
+// open.
+func gapState(g parser.Gap) string {
+	switch {
+	case g.Type == "A":
+		return "approved"
+	case g.Type == "T":
+		return "retirement"
+	case g.Resolved:
+		return "resolved"
+	}
+	return "open"
+}
@@ -1,9 +1,10 @@
-// CRC: crc-Query.md | Seq: seq-query.md
+// CRC: crc-Query.md | Seq: seq-query.md | R97, R103, R116, R119, R120, R123, R133
 package query
 
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
@@ -26,6 +27,60 @@ func (q *Query) Requirements() ([]parser.Requirement, error) {
 	return parser.ParseRequirements(q.Project.RequirementsPath())
 }
 
+// RequirementFilter selects requirements by metadata. Empty fields match
+// everything; Status "open" matches requirements with no status. R120
+type RequirementFilter struct {
+	Tag      string
+	Priority string
+	Status   string
+}
+
+// Match reports whether r passes the filter.
+func (f RequirementFilter) Match(r parser.Requirement) bool {
+	if f.Tag != "" && !slices.Contains(r.Tags, strings.TrimPrefix(f.Tag, "#")) {
+		return false
+	}
+	if f.Priority != "" && !strings.EqualFold(f.Priority, r.Priority) {
+		return false
+	}
+	return f.Status == "" || strings.EqualFold(f.Status, r.StatusOrOpen())
+}
+
+// FilterRequirements lists the requirements that pass f. R120
+func (q *Query) FilterRequirements(f RequirementFilter) ([]parser.Requirement, error) {
+	reqs, err := q.Requirements()
+	if err != nil {
+		return nil, err
+	}
+	var out []parser.Requirement
+	for _, r := range reqs {
+		if f.Match(r) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// SpecSection finds the heading a requirement Source anchor points to
+// (specs/auth.md#password-reset). ok is false when the Source has no anchor
+// or the spec has no such heading. R123
+func (q *Query) SpecSection(source string) (heading parser.SpecHeading, ok bool, err error) {
+	file, anchor, found := strings.Cut(source, "#")
+	if !found || anchor == "" {
+		return parser.SpecHeading{}, false, nil
+	}
+	headings, err := parser.ParseSpecHeadings(filepath.Join(q.Project.RootPath, file))
+	if err != nil {
+		return parser.SpecHeading{}, false, err
+	}
+	for _, h := range headings {
+		if h.Anchor == anchor {
+			return h, true, nil
+		}
+	}
+	return parser.SpecHeading{}, false, nil
+}
+
 // CoverageResult maps requirement IDs to files that reference them
 type CoverageResult struct {
 	Coverage map[string][]string // Rn -> []file paths
@@ -68,17 +123,64 @@ func (q *Query) Coverage() (*CoverageResult, error) {
 	return result, nil
 }
 
-// Uncovered returns requirements with no design file references
-func (q *Query) Uncovered() ([]string, error) {
+// RequirementCoverage is a requirement with the design files that list it,
+// the record `query coverage` lists. R133
+type RequirementCoverage struct {
+	ID    string
+	Files []string
+}
+
+// CoverageList lists Coverage per requirement, in requirements.md order;
+// IDs that CRC cards list but requirements.md lacks follow, sorted. R133
+func (q *Query) CoverageList() ([]RequirementCoverage, error) {
 	cov, err := q.Coverage()
 	if err != nil {
 		return nil, err
 	}
+	reqs, err := q.Requirements()
+	if err != nil {
+		return nil, err
+	}
+	var list []RequirementCoverage
+	for _, r := range reqs {
+		if files, ok := cov.Coverage[r.ID]; ok {
+			list = append(list, RequirementCoverage{ID: r.ID, Files: files})
+			delete(cov.Coverage, r.ID)
+		}
+	}
+	var unknown []string
+	for id := range cov.Coverage {
+		unknown = append(unknown, id)
+	}
+	sort.Strings(unknown)
+	for _, id := range unknown {
+		list = append(list, RequirementCoverage{ID: id, Files: cov.Coverage[id]})
+	}
+	return list, nil
+}
+
+// Uncovered returns requirements with no design file references, leaving
+// out deferred ones (R119)
+func (q *Query) Uncovered() ([]string, error) {
+	cov, err := q.CoverageList()
+	if err != nil {
+		return nil, err
+	}
+	reqs, err := q.Requirements()
+	if err != nil {
+		return nil, err
+	}
+	deferred := make(map[string]bool)
+	for _, r := range reqs {
+		if r.Deferred() {
+			deferred[r.ID] = true
+		}
+	}
 
 	var uncovered []string
-	for id, files := range cov.Coverage {
-		if len(files) == 0 {
-			uncovered = append(uncovered, id)
+	for _, c := range cov {
+		if len(c.Files) == 0 && !deferred[c.ID] {
+			uncovered = append(uncovered, c.ID)
 		}
 	}
 	return uncovered, nil
@@ -109,11 +211,111 @@ func (q *Query) Artifacts() ([]parser.Artifact, error) {
 	return parser.ParseArtifacts(q.Project.DesignMdPath())
 }
 
+// ArtifactFile is one code file of an Artifacts line with the line's design
+// file, the record `query artifacts` lists. R133
+type ArtifactFile struct {
+	DesignFile string
+	parser.CodeFile
+}
+
+// ArtifactFiles lists the code files of every Artifacts line, in file order.
+// R133
+func (q *Query) ArtifactFiles() ([]ArtifactFile, error) {
+	artifacts, err := q.Artifacts()
+	if err != nil {
+		return nil, err
+	}
+	var files []ArtifactFile
+	for _, art := range artifacts {
+		for _, cf := range art.CodeFiles {
+			files = append(files, ArtifactFile{DesignFile: art.DesignFile, CodeFile: cf})
+		}
+	}
+	return files, nil
+}
+
 // Gaps lists all gap items from design.md
 func (q *Query) Gaps() ([]parser.Gap, error) {
 	return parser.ParseGaps(q.Project.DesignMdPath())
 }
 
+// CRC parses one CRC card given as a path, a design filename
+// (crc-Store.md) or a card name (Store). R116
+func (q *Query) CRC(name string) (parser.CRCCard, error) {
+	path := name
+	if _, err := os.Stat(path); err != nil {
+		if !strings.HasSuffix(name, ".md") {
+			name = "crc-" + name + ".md"
+		}
+		path = q.Project.DesignPath(name)
+	}
+	return parser.ParseCRCCard(path)
+}
+
+// CollaboratorEdge is one CRC Collaborators entry: card From lists To. R103
+type CollaboratorEdge struct {
+	From   string
+	To     string
+	Note   string
+	Mutual bool // To is a card whose Collaborators list From back
+	NoCard bool // To has no CRC card (an external or unknown name)
+}
+
+// Collaborators returns the collaboration graph from the CRC cards' Collaborators
+// lists, sorted by From then To. With a card name (Store or crc-Store.md) only
+// edges touching that card are returned. R103
+func (q *Query) Collaborators(card string) ([]CollaboratorEdge, error) {
+	files, err := q.Project.GlobCRCCards()
+	if err != nil {
+		return nil, err
+	}
+	lists := make(map[string]map[string]string) // card -> collaborator -> note
+	for _, path := range files {
+		c, err := parser.ParseCRCCard(path)
+		if err != nil {
+			continue
+		}
+		name := parser.CardName(path)
+		lists[name] = make(map[string]string)
+		for _, col := range c.Collaborators {
+			lists[name][col.Name] = col.Note
+		}
+	}
+
+	focus := ""
+	if card != "" {
+		focus = parser.CardName(card)
+	}
+	var edges []CollaboratorEdge
+	for from, cols := range lists {
+		for to, note := range cols {
+			if focus != "" && from != focus && to != focus {
+				continue
+			}
+			_, isCard := lists[to]
+			_, mutual := lists[to][from]
+			edges = append(edges, CollaboratorEdge{From: from, To: to, Note: note, Mutual: mutual, NoCard: !isCard})
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges, nil
+}
+
+// Sequence parses a sequence diagram given as a design filename
+// (seq-crud.md) or a path. R97
+func (q *Query) Sequence(name string) (parser.Sequence, error) {
+	path := name
+	if _, err := os.Stat(path); err != nil {
+		path = q.Project.DesignPath(name)
+	}
+	return parser.ParseSequence(path)
+}
+
 // Migrations lists in-flight migration spec files (specs/migrations/*.md, R79).
 // Excludes specs/migrations/complete/. Returns relative paths from project root,
 // sorted lexically. Returns an empty slice (not error) when no migrations dir exists.
@@ -160,7 +362,7 @@ func (q *Query) TraceabilityAll() (map[string]parser.Traceability, error) {
 	result := make(map[string]parser.Traceability)
 	for _, art := range artifacts {
 		for _, cf := range art.CodeFiles {
-			trace, err := q.Traceability(cf.Path)
+			trace, err := q.Traceability(filepath.Join(q.Project.RootPath, cf.Path))
 			if err != nil {
 				// File might not exist yet
 				result[cf.Path] = parser.Traceability{}
@@ -172,6 +374,34 @@ func (q *Query) TraceabilityAll() (map[string]parser.Traceability, error) {
 	return result, nil
 }
 
+// FileTraceability is a code file's traceability comments, the record
+// `query traceability --all` lists. R133
+type FileTraceability struct {
+	Path string
+	parser.Traceability
+}
+
+// TraceabilityList lists TraceabilityAll per code file, in Artifacts order.
+// R133
+func (q *Query) TraceabilityList() ([]FileTraceability, error) {
+	files, err := q.ArtifactFiles()
+	if err != nil {
+		return nil, err
+	}
+	traces, err := q.TraceabilityAll()
+	if err != nil {
+		return nil, err
+	}
+	var list []FileTraceability
+	for _, f := range files {
+		if trace, ok := traces[f.Path]; ok {
+			list = append(list, FileTraceability{Path: f.Path, Traceability: trace})
+			delete(traces, f.Path)
+		}
+	}
+	return list, nil
+}
+
 // CommentPatterns returns the configured comment patterns per file extension
 func (q *Query) CommentPatterns() map[string]string {
 	return q.Project.Config.CommentPatterns
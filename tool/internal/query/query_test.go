@@ -0,0 +1,129 @@
+// CRC: crc-Query.md | R103, R133
+package query
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/zot/minispec/internal/parser"
+	"github.com/zot/minispec/internal/project"
+)
+
+// testProject writes files (paths relative to the project root) and returns
+// a Query over the project.
+func testProject(t *testing.T, files map[string]string) *Query {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	design := filepath.Join(root, "design")
+	if err := os.MkdirAll(design, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return New(&project.Project{RootPath: root, DesignDir: design, Config: project.DefaultConfig()})
+}
+
+func TestCollaborators(t *testing.T) {
+	q := testProject(t, map[string]string{
+		"design/crc-App.md":   "# App\n\n## Collaborators\n- Store: saves contacts\n- Logger\n",
+		"design/crc-Store.md": "# Store\n\n## Collaborators\n- App\n",
+		"design/crc-View.md":  "# View\n\n## Collaborators\n- Store\n",
+	})
+
+	all, err := q.Collaborators("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CollaboratorEdge{
+		{From: "App", To: "Logger", NoCard: true},
+		{From: "App", To: "Store", Note: "saves contacts", Mutual: true},
+		{From: "Store", To: "App", Mutual: true},
+		{From: "View", To: "Store"},
+	}
+	if !reflect.DeepEqual(all, want) {
+		t.Errorf("Collaborators(\"\") = %+v, want %+v", all, want)
+	}
+
+	for _, card := range []string{"View", "crc-View.md"} {
+		focused, err := q.Collaborators(card)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []CollaboratorEdge{{From: "View", To: "Store"}}; !reflect.DeepEqual(focused, want) {
+			t.Errorf("Collaborators(%q) = %+v, want %+v", card, focused, want)
+		}
+	}
+}
+
+func TestRecordLists(t *testing.T) {
+	q := traceProject(t)
+
+	cov, err := q.CoverageList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, c := range cov {
+		ids = append(ids, c.ID)
+	}
+	if want := []string{"R1", "R2", "R3", "R4", "R5", "R6", "R7"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("CoverageList IDs = %v, want %v", ids, want)
+	}
+	if c := cov[4]; len(c.Files) != 1 || filepath.Base(c.Files[0]) != "crc-Exporter.md" {
+		t.Errorf("CoverageList R5 = %+v", c)
+	}
+	uncovered, err := q.Uncovered()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"R6", "R7"}; !reflect.DeepEqual(uncovered, want) {
+		t.Errorf("Uncovered = %v, want %v", uncovered, want)
+	}
+
+	files, err := q.ArtifactFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := ParseWhere(`checked == false || design_file =~ "^seq"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err = Filter(w, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ArtifactFile{
+		{DesignFile: "crc-Exporter.md", CodeFile: parser.CodeFile{Path: "src/export.ts", Line: 5}},
+		{DesignFile: "seq-save.md", CodeFile: parser.CodeFile{Path: "src/store.ts", Checked: true, Line: 6}},
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("ArtifactFiles where %s =\n %+v\nwant\n %+v", w, files, want)
+	}
+
+	traces, err := q.TraceabilityList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, tr := range traces {
+		paths = append(paths, tr.Path)
+	}
+	if want := []string{"src/store.ts", "src/export.ts"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("TraceabilityList paths = %v, want %v", paths, want)
+	}
+	if w, _ = ParseWhere(`seq_refs contains "seq-save.md"`); w == nil {
+		t.Fatal("bad expression")
+	}
+	if traces, err = Filter(w, traces); err != nil || len(traces) != 1 || traces[0].Path != "src/store.ts" {
+		t.Errorf("TraceabilityList where %s = %+v, %v", w, traces, err)
+	}
+}
@@ -0,0 +1,115 @@
+// CRC: crc-Query.md | Seq: seq-query.md | R135
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/zot/minispec/internal/parser"
+)
+
+// retirementGapRe matches a T gap description written by update retire:
+// "R5 retired by R10 (reason)" or "R5 retired (reason)".
+var retirementGapRe = regexp.MustCompile(`^(R\d+) retired(?: by (R\d+))?\s*\((.*)\)\s*$`)
+
+// RetirementStep is one link of a retirement chain: Requirement was retired
+// by Gap in favour of ReplacedBy ("" for no replacement). R135
+type RetirementStep struct {
+	Requirement string
+	Gap         string
+	ReplacedBy  string
+	Reason      string // from the T gap's description
+}
+
+// RequirementResult is one requirement with its retirement chain followed
+// to the live requirement that now stands for it. R135
+type RequirementResult struct {
+	Requirement parser.Requirement
+	Chain       []RetirementStep
+	Current     string // live requirement the chain ends at, "" when it does not
+	DeadEnd     string // why the chain ends without one: no replacement or a missing Rn
+	Cycle       []string
+}
+
+// Requirement looks up one requirement and, when it is retired, follows its
+// replacements (R5 -> R10 -> R22) to a live requirement, stopping at a
+// requirement retired with no replacement, a replacement that does not
+// exist, or a cycle. Replacements and reasons come from the requirement's
+// retired marker and its T gap. R135
+func (q *Query) Requirement(id string) (*RequirementResult, error) {
+	reqs, err := q.Requirements()
+	if err != nil {
+		return nil, err
+	}
+	gaps, err := q.Gaps()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]parser.Requirement)
+	for _, r := range reqs {
+		byID[r.ID] = r
+	}
+	req, ok := byID[id]
+	if !ok {
+		return nil, fmt.Errorf("requirement %s not found", id)
+	}
+
+	result := &RequirementResult{Requirement: req}
+	seen := map[string]bool{}
+	path := []string{}
+	for r := req; ; {
+		if seen[r.ID] {
+			start := 0
+			for path[start] != r.ID {
+				start++
+			}
+			result.Cycle = append(path[start:], r.ID)
+			return result, nil
+		}
+		seen[r.ID] = true
+		path = append(path, r.ID)
+		if !r.Retired {
+			result.Current = r.ID
+			return result, nil
+		}
+
+		step := retirementStep(r, gaps)
+		result.Chain = append(result.Chain, step)
+		if step.ReplacedBy == "" {
+			result.DeadEnd = r.ID + " retired with no replacement"
+			return result, nil
+		}
+		next, ok := byID[step.ReplacedBy]
+		if !ok {
+			result.DeadEnd = fmt.Sprintf("%s replaced by %s, which does not exist", r.ID, step.ReplacedBy)
+			return result, nil
+		}
+		r = next
+	}
+}
+
+// retirementStep describes how r was retired, from its marker and the T gap
+// it names (or, without a Tn, the T gap recording its retirement).
+func retirementStep(r parser.Requirement, gaps []parser.Gap) RetirementStep {
+	step := RetirementStep{Requirement: r.ID, Gap: r.RetiredBy, ReplacedBy: r.ReplacedBy}
+	for _, g := range gaps {
+		if g.Type != "T" || (step.Gap != "" && g.ID != step.Gap) {
+			continue
+		}
+		m := retirementGapRe.FindStringSubmatch(strings.TrimSpace(g.Description))
+		if m == nil || m[1] != r.ID {
+			if step.Gap != "" {
+				step.Reason = g.Description
+				break
+			}
+			continue
+		}
+		step.Gap, step.Reason = g.ID, m[3]
+		if step.ReplacedBy == "" {
+			step.ReplacedBy = m[2]
+		}
+		break
+	}
+	return step
+}
@@ -0,0 +1,75 @@
+// CRC: crc-Query.md | R135
+package query
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/zot/minispec/internal/project"
+)
+
+func TestRequirementRetirementChain(t *testing.T) {
+	root := t.TempDir()
+	design := filepath.Join(root, "design")
+	if err := os.Mkdir(design, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(design, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("requirements.md", `# Requirements
+
+## Feature: Chain
+**Source:** specs/chain.md
+
+- **~~R1:~~** (Retired T1 — see R2) first
+- **~~R2:~~** (Retired T2 — see R3) second
+- **R3:** third
+- **~~R4:~~** (Retired T3 — no replacement) gone
+- **~~R5:~~** (Retired T4 — see R6) loop a
+- **~~R6:~~** (Retired T5 — see R5) loop b
+- **~~R7:~~** (Retired T6 — see R99) missing
+`)
+	write("design.md", `# Design
+
+## Gaps
+- T1: R1 retired by R2 (merged into R2)
+- T2: R2 retired by R3 (split)
+- T3: R4 retired (obsolete)
+- T4: R5 retired by R6 (a)
+- T5: R6 retired by R5 (b)
+`)
+	q := New(&project.Project{RootPath: root, DesignDir: design, Config: project.DefaultConfig()})
+
+	res, err := q.Requirement("R1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []RetirementStep{
+		{Requirement: "R1", Gap: "T1", ReplacedBy: "R2", Reason: "merged into R2"},
+		{Requirement: "R2", Gap: "T2", ReplacedBy: "R3", Reason: "split"},
+	}
+	if !reflect.DeepEqual(res.Chain, want) || res.Current != "R3" {
+		t.Errorf("R1 chain = %+v, current %q", res.Chain, res.Current)
+	}
+
+	if res, _ := q.Requirement("R3"); res.Current != "R3" || len(res.Chain) != 0 {
+		t.Errorf("live R3 = %+v", res)
+	}
+	if res, _ := q.Requirement("R4"); res.DeadEnd != "R4 retired with no replacement" || res.Chain[0].Reason != "obsolete" {
+		t.Errorf("R4 = %+v", res)
+	}
+	if res, _ := q.Requirement("R7"); res.DeadEnd != "R7 replaced by R99, which does not exist" || res.Current != "" {
+		t.Errorf("R7 = %+v", res)
+	}
+	if res, _ := q.Requirement("R6"); !reflect.DeepEqual(res.Cycle, []string{"R6", "R5", "R6"}) {
+		t.Errorf("R6 cycle = %v", res.Cycle)
+	}
+	if _, err := q.Requirement("R42"); err == nil {
+		t.Error("expected an error for an unknown requirement")
+	}
+}
@@ -0,0 +1,160 @@
+// CRC: crc-Query.md | Seq: seq-query.md | R132
+package query
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/zot/minispec/internal/parser"
+)
+
+// SearchScopes lists the kinds of fields Search can look in.
+var SearchScopes = []string{"requirements", "crc", "seq", "gaps", "specs"}
+
+// SearchHit is one structured field matching a search. R132
+type SearchHit struct {
+	Kind  string // requirement, crc, seq, gap or spec
+	ID    string // Rn, card name, sequence filename, gap ID or heading anchor
+	Field string // text, knows, does, responsibilities, message, description or heading
+	File  string // relative to the project root
+	Line  int
+	Text  string
+}
+
+// Search matches a regular expression against requirement text, CRC
+// Knows/Does/Responsibilities items, sequence messages, gap descriptions and
+// spec headings. in limits the search to some of SearchScopes; empty means
+// all. Hits are grouped by scope in SearchScopes order, then in file order. R132
+func (q *Query) Search(pattern string, in []string) ([]SearchHit, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, scope := range in {
+		if !slices.Contains(SearchScopes, scope) {
+			return nil, fmt.Errorf("unknown search scope %q (want %s)", scope, strings.Join(SearchScopes, ", "))
+		}
+	}
+	want := func(scope string) bool { return len(in) == 0 || slices.Contains(in, scope) }
+	rel := func(path string) string {
+		if r, err := filepath.Rel(q.Project.RootPath, path); err == nil {
+			return filepath.ToSlash(r)
+		}
+		return path
+	}
+
+	var hits []SearchHit
+	if want("requirements") {
+		reqs, err := q.Requirements()
+		if err != nil {
+			return nil, err
+		}
+		file := rel(q.Project.RequirementsPath())
+		for _, r := range reqs {
+			if re.MatchString(r.Text) {
+				hits = append(hits, SearchHit{Kind: "requirement", ID: r.ID, Field: "text", File: file, Line: r.Line, Text: r.Text})
+			}
+		}
+	}
+
+	if want("crc") {
+		files, err := q.Project.GlobCRCCards()
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range files {
+			card, err := parser.ParseCRCCard(path)
+			if err != nil {
+				continue
+			}
+			lines, err := parser.ReadLines(path)
+			if err != nil {
+				continue
+			}
+			items := map[string][]string{"Knows": card.Knows, "Does": card.Does, "Responsibilities": card.Responsibilities}
+			for _, sec := range card.Sections {
+				for _, item := range items[sec.Title] {
+					if !re.MatchString(item) {
+						continue
+					}
+					line := sec.Line
+					for n := sec.Line + 1; n <= sec.EndLine && n <= len(lines); n++ {
+						if strings.Contains(lines[n-1], item) {
+							line = n
+							break
+						}
+					}
+					hits = append(hits, SearchHit{Kind: "crc", ID: parser.CardName(path), Field: strings.ToLower(sec.Title), File: rel(path), Line: line, Text: item})
+				}
+			}
+		}
+	}
+
+	if want("seq") {
+		files, err := q.Project.GlobSequences()
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range files {
+			seq, err := parser.ParseSequence(path)
+			if err != nil {
+				continue
+			}
+			for _, m := range seq.Messages {
+				arrow := "->"
+				if m.Reply {
+					arrow = "-->"
+				}
+				text := fmt.Sprintf("%s %s %s: %s", m.From, arrow, m.To, m.Text)
+				if m.From == m.To && !m.Reply {
+					text = m.From + ": " + m.Text
+				}
+				if re.MatchString(text) {
+					hits = append(hits, SearchHit{Kind: "seq", ID: filepath.Base(path), Field: "message", File: rel(path), Line: m.Line, Text: text})
+				}
+			}
+		}
+	}
+
+	if want("gaps") {
+		gaps, err := q.Gaps()
+		if err != nil {
+			return nil, err
+		}
+		file := rel(q.Project.DesignMdPath())
+		for _, g := range gaps {
+			if re.MatchString(g.Description) {
+				hits = append(hits, SearchHit{Kind: "gap", ID: g.ID, Field: "description", File: file, Line: g.Line, Text: g.Description})
+			}
+		}
+	}
+
+	if want("specs") {
+		var specs []string
+		err := filepath.WalkDir(q.Project.SpecsDir(), func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && strings.HasSuffix(path, ".md") {
+				specs = append(specs, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range specs {
+			headings, err := parser.ParseSpecHeadings(path)
+			if err != nil {
+				continue
+			}
+			for _, h := range headings {
+				if re.MatchString(h.Title) {
+					hits = append(hits, SearchHit{Kind: "spec", ID: "#" + h.Anchor, Field: "heading", File: rel(path), Line: h.Line, Text: h.Title})
+				}
+			}
+		}
+	}
+	return hits, nil
+}
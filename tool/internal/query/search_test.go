@@ -0,0 +1,56 @@
+// CRC: crc-Query.md | R132
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	q := traceProject(t)
+
+	// one hit per scope, grouped in SearchScopes order
+	hits, err := q.Search(`(?i)csv`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SearchHit{
+		{Kind: "requirement", ID: "R5", Field: "text", File: "design/requirements.md", Line: 14, Text: "Export contacts as CSV"},
+		{Kind: "crc", ID: "Exporter", Field: "responsibilities", File: "design/crc-Exporter.md", Line: 5, Text: "Write contacts as CSV"},
+		{Kind: "seq", ID: "seq-export.md", Field: "message", File: "design/seq-export.md", Line: 6, Text: "App -> Exporter: toCSV(contacts)"},
+		{Kind: "gap", ID: "D1", Field: "description", File: "design/design.md", Line: 9, Text: "CSV export ignores R5 quoting"},
+		{Kind: "spec", ID: "#csv", Field: "heading", File: "specs/export.md", Line: 3, Text: "CSV"},
+	}
+	if !reflect.DeepEqual(hits, want) {
+		t.Errorf("Search(csv) =\n %+v\nwant\n %+v", hits, want)
+	}
+
+	// scopes keep SearchScopes order whatever order they are given in
+	hits, err = q.Search(`(?i)csv`, []string{"gaps", "crc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(hits, []SearchHit{want[1], want[3]}) {
+		t.Errorf("Search(csv, gaps+crc) = %+v", hits)
+	}
+
+	// file order within a scope; the unreadable crc-Broken.md is skipped
+	hits, err = q.Search(`contacts by`, []string{"requirements", "crc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, h := range hits {
+		got = append(got, h.ID+" "+h.Field)
+	}
+	if want := []string{"R1 text", "R2 text", "R3 text", "Store knows", "Store does"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Search(contacts by) = %v, want %v", got, want)
+	}
+
+	if _, err := q.Search(`csv`, []string{"code"}); err == nil {
+		t.Error("unknown scope accepted")
+	}
+	if _, err := q.Search(`(`, nil); err == nil {
+		t.Error("invalid pattern accepted")
+	}
+}
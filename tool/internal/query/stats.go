@@ -0,0 +1,183 @@
+// CRC: crc-Query.md | Seq: seq-query.md | R131, R134
+package query
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/zot/minispec/internal/parser"
+)
+
+// RequirementStats counts a group of requirements and how far their design
+// and implementation have come. Coverage is measured over the tracked
+// requirements: active ones that are not deferred. R131
+type RequirementStats struct {
+	Name           string // feature name or spec path; empty for the project totals
+	Total          int
+	Active         int // not retired
+	Retired        int
+	Inferred       int // active and inferred
+	Deferred       int // active and deferred
+	Tracked        int // active and not deferred
+	DesignCovered  int // tracked, listed by a CRC card or an approved gap
+	ImplCovered    int // tracked, listed by a code traceability comment or an approved gap
+	DesignCoverage float64
+	ImplCoverage   float64
+}
+
+// ArtifactStats counts the code files listed in design.md Artifacts. R131
+type ArtifactStats struct {
+	Total      int
+	Checked    int
+	Completion float64
+}
+
+// GapStats counts design.md gaps by state. R131
+type GapStats struct {
+	Open     int
+	Resolved int
+	Approved int
+}
+
+// StatsResult holds project progress metrics, with requirement metrics
+// broken down per feature (in file order) and per source spec file (in
+// order of first use). R131
+type StatsResult struct {
+	Requirements RequirementStats
+	Features     []RequirementStats
+	Specs        []RequirementStats
+	Artifacts    ArtifactStats
+	Gaps         GapStats
+}
+
+// Percent returns n as a percentage of total, 0 when total is 0.
+func Percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
+
+// add counts r into s.
+func (s *RequirementStats) add(r parser.Requirement, design, impl bool) {
+	s.Total++
+	switch {
+	case r.Retired:
+		s.Retired++
+		return
+	case r.Deferred():
+		s.Deferred++
+	default:
+		s.Tracked++
+		if design {
+			s.DesignCovered++
+		}
+		if impl {
+			s.ImplCovered++
+		}
+	}
+	s.Active++
+	if r.Inferred {
+		s.Inferred++
+	}
+}
+
+func (s *RequirementStats) percentages() {
+	s.DesignCoverage = Percent(s.DesignCovered, s.Tracked)
+	s.ImplCoverage = Percent(s.ImplCovered, s.Tracked)
+}
+
+// Stats computes requirement counts, design and implementation coverage,
+// Artifacts checkbox completion and gap counts, per project, feature and
+// source spec. R131, R134
+func (q *Query) Stats() (*StatsResult, error) {
+	reqs, err := q.Requirements()
+	if err != nil {
+		return nil, err
+	}
+	gaps, err := q.Gaps()
+	if err != nil {
+		return nil, err
+	}
+	artifacts, err := q.Artifacts()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StatsResult{}
+	approved := make(map[string]bool)
+	for _, g := range gaps {
+		switch {
+		case g.Type == "A":
+			result.Gaps.Approved++
+			for _, r := range reqs {
+				if mentionsReq(g.Description, r.ID) {
+					approved[r.ID] = true
+				}
+			}
+		case g.Type == "T":
+		case g.Resolved:
+			result.Gaps.Resolved++
+		default:
+			result.Gaps.Open++
+		}
+	}
+
+	designed := make(map[string]bool)
+	cov, err := q.Coverage()
+	if err != nil {
+		return nil, err
+	}
+	for id, files := range cov.Coverage {
+		designed[id] = len(files) > 0
+	}
+
+	implemented := make(map[string]bool)
+	for _, art := range artifacts {
+		for _, cf := range art.CodeFiles {
+			result.Artifacts.Total++
+			if cf.Checked {
+				result.Artifacts.Checked++
+			}
+			path := filepath.Join(q.Project.RootPath, cf.Path)
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			trace, err := q.Traceability(path)
+			if err != nil {
+				continue
+			}
+			for _, ref := range trace.ReqRefs {
+				implemented[ref] = true
+			}
+		}
+	}
+	result.Artifacts.Completion = Percent(result.Artifacts.Checked, result.Artifacts.Total)
+
+	featureIndex := make(map[string]int)
+	specIndex := make(map[string]int)
+	group := func(list *[]RequirementStats, index map[string]int, name string) *RequirementStats {
+		i, ok := index[name]
+		if !ok {
+			i = len(*list)
+			index[name] = i
+			*list = append(*list, RequirementStats{Name: name})
+		}
+		return &(*list)[i]
+	}
+	for _, r := range reqs {
+		design := designed[r.ID] || approved[r.ID]
+		impl := implemented[r.ID] || approved[r.ID]
+		result.Requirements.add(r, design, impl)
+		group(&result.Features, featureIndex, r.Feature).add(r, design, impl)
+		group(&result.Specs, specIndex, r.SourceFile()).add(r, design, impl)
+	}
+	result.Requirements.percentages()
+	for i := range result.Features {
+		result.Features[i].percentages()
+	}
+	for i := range result.Specs {
+		result.Specs[i].percentages()
+	}
+	return result, nil
+}
@@ -0,0 +1,53 @@
+// CRC: crc-Query.md | R131, R134
+package query
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	res, err := traceProject(t).Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// R1 and R5 are designed and implemented, R2 only designed, R6 both through
+	// approved gap A1 and R7 neither; R3 (retired) and R4 (deferred) are not tracked.
+	contacts := RequirementStats{Name: "Contacts", Total: 4, Active: 3, Retired: 1, Inferred: 1, Deferred: 1,
+		Tracked: 2, DesignCovered: 2, ImplCovered: 1, DesignCoverage: 100, ImplCoverage: 50}
+	export := RequirementStats{Name: "Export", Total: 3, Active: 3,
+		Tracked: 3, DesignCovered: 2, ImplCovered: 2, DesignCoverage: 200.0 / 3, ImplCoverage: 200.0 / 3}
+	total := RequirementStats{Total: 7, Active: 6, Retired: 1, Inferred: 1, Deferred: 1,
+		Tracked: 5, DesignCovered: 4, ImplCovered: 3, DesignCoverage: 80, ImplCoverage: 60}
+
+	if !statsEqual(res.Requirements, total) {
+		t.Errorf("totals = %+v, want %+v", res.Requirements, total)
+	}
+	if len(res.Features) != 2 || !statsEqual(res.Features[0], contacts) || !statsEqual(res.Features[1], export) {
+		t.Errorf("features = %+v", res.Features)
+	}
+	contacts.Name, export.Name = "specs/contacts.md", "specs/export.md"
+	if len(res.Specs) != 2 || !statsEqual(res.Specs[0], contacts) || !statsEqual(res.Specs[1], export) {
+		t.Errorf("specs = %+v", res.Specs)
+	}
+	if a := res.Artifacts; a.Total != 3 || a.Checked != 2 || math.Abs(a.Completion-200.0/3) > 1e-9 {
+		t.Errorf("artifacts = %+v", a)
+	}
+	if want := (GapStats{Open: 1, Resolved: 1, Approved: 1}); res.Gaps != want {
+		t.Errorf("gaps = %+v, want %+v", res.Gaps, want)
+	}
+
+	if Percent(1, 0) != 0 || Percent(1, 4) != 25 {
+		t.Error("Percent is wrong")
+	}
+}
+
+// statsEqual compares requirement stats, allowing for rounding in the percentages.
+func statsEqual(a, b RequirementStats) bool {
+	if math.Abs(a.DesignCoverage-b.DesignCoverage) > 1e-9 || math.Abs(a.ImplCoverage-b.ImplCoverage) > 1e-9 {
+		return false
+	}
+	a.DesignCoverage, a.ImplCoverage = b.DesignCoverage, b.ImplCoverage
+	return reflect.DeepEqual(a, b)
+}
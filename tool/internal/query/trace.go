@@ -0,0 +1,141 @@
+// CRC: crc-Query.md | Seq: seq-query.md | R127
+package query
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/zot/minispec/internal/parser"
+)
+
+// reqRefRe matches Rn or an Rn-Rm range in free text such as gap descriptions.
+var reqRefRe = regexp.MustCompile(`\bR(\d+)(?:-R(\d+))?\b`)
+
+// TraceResult is the vertical slice of one requirement: where it is
+// specified, designed and implemented. R127
+type TraceResult struct {
+	Requirement parser.Requirement
+	Section     *parser.SpecHeading // heading an anchored Source points to
+	CRCCards    []string            // crc filenames listing the requirement
+	Sequences   []string            // seq filenames mentioning it or listed by those cards
+	Code        []CodeRef           // traceability comments listing it
+	Gaps        []parser.Gap        // gaps whose description mentions it
+	Artifacts   []ArtifactState     // Artifacts lines of the cards and sequences above
+}
+
+// CodeRef is a traceability comment at a line of a code file.
+type CodeRef struct {
+	File string
+	Line int
+}
+
+// ArtifactState is one Artifacts entry: a design file and its code files,
+// each with its own checkbox (an inline line's checkbox applies to all of
+// them).
+type ArtifactState struct {
+	DesignFile string
+	CodeFiles  []parser.CodeFile
+}
+
+// Trace collects the spec source, design files, code comments, gaps and
+// artifact states of requirement id. R127
+func (q *Query) Trace(id string) (*TraceResult, error) {
+	reqs, err := q.Requirements()
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(reqs, func(r parser.Requirement) bool { return r.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("requirement %s not found", id)
+	}
+	result := &TraceResult{Requirement: reqs[i]}
+	if h, ok, _ := q.SpecSection(reqs[i].Source); ok {
+		result.Section = &h
+	}
+
+	cards, err := q.Project.GlobCRCCards()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range cards {
+		card, err := parser.ParseCRCCard(path)
+		if err != nil || !slices.Contains(card.Requirements, id) {
+			continue
+		}
+		result.CRCCards = append(result.CRCCards, filepath.Base(path))
+		result.Sequences = append(result.Sequences, card.Sequences...)
+	}
+	seqs, err := q.Project.GlobSequences()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range seqs {
+		if data, err := os.ReadFile(path); err == nil && mentionsReq(string(data), id) {
+			result.Sequences = append(result.Sequences, filepath.Base(path))
+		}
+	}
+	slices.Sort(result.Sequences)
+	result.Sequences = slices.Compact(result.Sequences)
+
+	artifacts, err := q.Artifacts()
+	if err != nil {
+		return nil, err
+	}
+	scanned := make(map[string]bool)
+	for _, art := range artifacts {
+		if slices.Contains(result.CRCCards, art.DesignFile) || slices.Contains(result.Sequences, art.DesignFile) {
+			result.Artifacts = append(result.Artifacts, ArtifactState{DesignFile: art.DesignFile, CodeFiles: art.CodeFiles})
+		}
+		for _, cf := range art.CodeFiles {
+			if scanned[cf.Path] {
+				continue
+			}
+			scanned[cf.Path] = true
+			trace, err := q.Traceability(filepath.Join(q.Project.RootPath, cf.Path))
+			if err != nil {
+				continue
+			}
+			for _, c := range trace.Comments {
+				if slices.Contains(c.ReqRefs, id) {
+					result.Code = append(result.Code, CodeRef{File: cf.Path, Line: c.Line})
+				}
+			}
+		}
+	}
+
+	gaps, err := q.Gaps()
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range gaps {
+		if mentionsReq(g.Description, id) {
+			result.Gaps = append(result.Gaps, g)
+		}
+	}
+	return result, nil
+}
+
+// mentionsReq reports whether text names requirement id, alone or inside
+// an Rn-Rm range.
+func mentionsReq(text, id string) bool {
+	n, err := strconv.Atoi(strings.TrimPrefix(id, "R"))
+	if err != nil {
+		return false
+	}
+	for _, m := range reqRefRe.FindAllStringSubmatch(text, -1) {
+		lo, _ := strconv.Atoi(m[1])
+		hi := lo
+		if m[2] != "" {
+			hi, _ = strconv.Atoi(m[2])
+		}
+		if lo <= n && n <= hi {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+// CRC: crc-Query.md | R127
+package query
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zot/minispec/internal/parser"
+)
+
+func TestTrace(t *testing.T) {
+	q := traceProject(t)
+
+	res, err := q.Trace("R1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Requirement.ID != "R1" || res.Section != nil {
+		t.Errorf("R1 requirement = %+v, section %+v", res.Requirement, res.Section)
+	}
+	if !reflect.DeepEqual(res.CRCCards, []string{"crc-Store.md"}) || !reflect.DeepEqual(res.Sequences, []string{"seq-save.md"}) {
+		t.Errorf("R1 design = %v, %v", res.CRCCards, res.Sequences)
+	}
+	if want := []CodeRef{{File: "src/store.ts", Line: 1}}; !reflect.DeepEqual(res.Code, want) {
+		t.Errorf("R1 code = %+v, want %+v", res.Code, want)
+	}
+	wantArtifacts := []ArtifactState{
+		{DesignFile: "crc-Store.md", CodeFiles: []parser.CodeFile{{Path: "src/store.ts", Checked: true, Line: 4}}},
+		{DesignFile: "seq-save.md", CodeFiles: []parser.CodeFile{{Path: "src/store.ts", Checked: true, Line: 6}}},
+	}
+	if !reflect.DeepEqual(res.Artifacts, wantArtifacts) || len(res.Gaps) != 0 {
+		t.Errorf("R1 artifacts = %+v, gaps %+v", res.Artifacts, res.Gaps)
+	}
+
+	// anchored source, a sequence that only mentions R5, a gap and an unchecked line
+	res, err = q.Trace("R5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Section == nil || res.Section.Title != "CSV" || res.Section.Line != 3 {
+		t.Errorf("R5 section = %+v", res.Section)
+	}
+	if !reflect.DeepEqual(res.CRCCards, []string{"crc-Exporter.md"}) || !reflect.DeepEqual(res.Sequences, []string{"seq-export.md"}) {
+		t.Errorf("R5 design = %v, %v", res.CRCCards, res.Sequences)
+	}
+	if want := []CodeRef{{File: "src/export.ts", Line: 1}}; !reflect.DeepEqual(res.Code, want) {
+		t.Errorf("R5 code = %+v, want %+v", res.Code, want)
+	}
+	if want := []ArtifactState{{DesignFile: "crc-Exporter.md", CodeFiles: []parser.CodeFile{{Path: "src/export.ts", Line: 5}}}}; !reflect.DeepEqual(res.Artifacts, want) {
+		t.Errorf("R5 artifacts = %+v, want %+v", res.Artifacts, want)
+	}
+	if len(res.Gaps) != 1 || res.Gaps[0].ID != "D1" {
+		t.Errorf("R5 gaps = %+v", res.Gaps)
+	}
+
+	// a retired requirement is still traced to what names it
+	res, err = q.Trace("R3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := res.Requirement; !r.Retired || r.ReplacedBy != "R2" || r.Text != "Filter contacts by name" {
+		t.Errorf("R3 requirement = %+v", r)
+	}
+	if !reflect.DeepEqual(res.CRCCards, []string{"crc-Store.md"}) || !reflect.DeepEqual(res.Code, []CodeRef{{File: "src/store.ts", Line: 1}}) {
+		t.Errorf("R3 = %v, %+v", res.CRCCards, res.Code)
+	}
+	if want := []parser.Gap{{ID: "T1", Type: "T", Description: "R3 retired by R2 (merged into search)", Line: 12}}; !reflect.DeepEqual(res.Gaps, want) {
+		t.Errorf("R3 gaps = %+v, want %+v", res.Gaps, want)
+	}
+
+	if _, err := q.Trace("R99"); err == nil {
+		t.Error("Trace(R99) accepted an unknown requirement")
+	}
+}
+
+func TestTraceMultiLineArtifact(t *testing.T) {
+	q := testProject(t, map[string]string{
+		"design/requirements.md": "# Requirements\n\n- **R1:** Store contacts\n",
+		"design/crc-Store.md":    "# CRC: Store\n**Requirements:** R1\n",
+		"design/design.md":       "# Design\n\n## Artifacts\n- crc-Store.md\n  - [x] src/store.ts\n  - [ ] src/cache.ts\n\n## Gaps\n",
+	})
+	res, err := q.Trace("R1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// each code file keeps its own checkbox
+	want := []ArtifactState{{DesignFile: "crc-Store.md", CodeFiles: []parser.CodeFile{
+		{Path: "src/store.ts", Checked: true, Line: 5},
+		{Path: "src/cache.ts", Line: 6},
+	}}}
+	if !reflect.DeepEqual(res.Artifacts, want) {
+		t.Errorf("artifacts = %+v, want %+v", res.Artifacts, want)
+	}
+}
@@ -0,0 +1,613 @@
+// CRC: crc-Query.md | Seq: seq-query.md | R133
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Where is a parsed --where filter expression, evaluated against the fields
+// of query records:
+//
+//	retired == false && source == "specs/auth.md"
+//	type in ["D", "C"] && !resolved
+//	tags contains "security" || text =~ "(?i)login"
+//
+// Identifiers name struct fields case-insensitively (underscores ignored,
+// dots for nested fields). Values are strings, numbers, true/false, null and
+// [lists]; operators are == != < <= > >= =~ !~ in contains ! && || and
+// parentheses. A bare operand is true when it is not false, null, zero or
+// empty. R133
+type Where struct {
+	expr string
+	root whereNode
+}
+
+// ParseWhere parses a filter expression. R133
+func ParseWhere(expr string) (*Where, error) {
+	toks, err := lexWhere(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &whereParser{toks: toks}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, p.errorf("unexpected %q", p.peek().text)
+	}
+	return &Where{expr: expr, root: root}, nil
+}
+
+// String returns the expression as given.
+func (w *Where) String() string {
+	return w.expr
+}
+
+// Match evaluates the expression against a record. Identifiers are looked up
+// in each record in turn, so a nested record (an Artifacts code file) can be
+// given before its parent (the Artifacts line). R133
+func (w *Where) Match(records ...any) (bool, error) {
+	v, err := w.root.eval(records)
+	if err != nil {
+		return false, fmt.Errorf("where %s: %w", w.expr, err)
+	}
+	return truthy(v), nil
+}
+
+// Filter returns the records w matches; a nil w matches everything. R133
+func Filter[T any](w *Where, records []T) ([]T, error) {
+	if w == nil {
+		return records, nil
+	}
+	var out []T
+	for _, r := range records {
+		ok, err := w.Match(r)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// FilterStrings filters a list of plain values (Rn IDs, paths), each seen as
+// a record with the single field name. R133
+func FilterStrings(w *Where, values []string, name string) ([]string, error) {
+	if w == nil {
+		return values, nil
+	}
+	var out []string
+	for _, v := range values {
+		ok, err := w.Match(map[string]string{name: v})
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// Lexer
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+)
+
+type whereTok struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+// compareOps lists the binary operators that compare two operands.
+var compareOps = []string{"==", "!=", "<", "<=", ">", ">=", "=~", "!~"}
+
+// whereOps lists the operators, longest first.
+var whereOps = []string{"==", "!=", "<=", ">=", "=~", "!~", "&&", "||", "<", ">", "!", "(", ")", "[", "]", ","}
+
+func lexWhere(s string) ([]whereTok, error) {
+	var toks []whereTok
+	i := 0
+next:
+	for i < len(s) {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+			continue
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(s) && s[end] != byte(c) {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return nil, fmt.Errorf("where: unterminated string at %d", i+1)
+			}
+			text := s[i+1 : end]
+			if c == '"' {
+				unq, err := strconv.Unquote(s[i : end+1])
+				if err != nil {
+					return nil, fmt.Errorf("where: bad string at %d: %v", i+1, err)
+				}
+				text = unq
+			}
+			toks = append(toks, whereTok{tokString, text, i + 1})
+			i = end + 1
+			continue
+		case unicode.IsDigit(c) || (c == '-' && i+1 < len(s) && unicode.IsDigit(rune(s[i+1]))):
+			end := i + 1
+			for end < len(s) && (unicode.IsDigit(rune(s[end])) || s[end] == '.') {
+				end++
+			}
+			toks = append(toks, whereTok{tokNumber, s[i:end], i + 1})
+			i = end
+			continue
+		case unicode.IsLetter(c) || c == '_':
+			end := i + 1
+			for end < len(s) && (unicode.IsLetter(rune(s[end])) || unicode.IsDigit(rune(s[end])) || s[end] == '_' || s[end] == '.') {
+				end++
+			}
+			toks = append(toks, whereTok{tokIdent, s[i:end], i + 1})
+			i = end
+			continue
+		}
+		for _, op := range whereOps {
+			if strings.HasPrefix(s[i:], op) {
+				toks = append(toks, whereTok{tokOp, op, i + 1})
+				i += len(op)
+				continue next
+			}
+		}
+		return nil, fmt.Errorf("where: unexpected %q at %d", c, i+1)
+	}
+	return append(toks, whereTok{tokEOF, "end of expression", len(s) + 1}), nil
+}
+
+// Parser
+
+type whereParser struct {
+	toks []whereTok
+	pos  int
+}
+
+func (p *whereParser) peek() whereTok { return p.toks[p.pos] }
+
+func (p *whereParser) next() whereTok {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *whereParser) accept(op string) bool {
+	if t := p.peek(); (t.kind == tokOp || t.kind == tokIdent) && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *whereParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("where: "+format+" at %d", append(args, p.peek().pos)...)
+}
+
+func (p *whereParser) or() (whereNode, error) {
+	left, err := p.and()
+	for err == nil && p.accept("||") {
+		var right whereNode
+		if right, err = p.and(); err == nil {
+			left = &logicNode{op: "||", left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *whereParser) and() (whereNode, error) {
+	left, err := p.unary()
+	for err == nil && p.accept("&&") {
+		var right whereNode
+		if right, err = p.unary(); err == nil {
+			left = &logicNode{op: "&&", left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *whereParser) unary() (whereNode, error) {
+	if p.accept("!") {
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand}, nil
+	}
+	return p.comparison()
+}
+
+func (p *whereParser) comparison() (whereNode, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	switch {
+	case t.kind == tokOp && slices.Contains(compareOps, t.text):
+	case t.kind == tokIdent && (t.text == "in" || t.text == "contains"):
+	default:
+		return left, nil
+	}
+	p.next()
+	right, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	cmp := &compareNode{op: t.text, left: left, right: right}
+	if t.text == "=~" || t.text == "!~" {
+		if lit, ok := right.(*literalNode); ok {
+			s, isString := lit.value.(string)
+			if !isString {
+				return nil, fmt.Errorf("where: %s needs a string pattern at %d", t.text, t.pos)
+			}
+			if cmp.re, err = regexp.Compile(s); err != nil {
+				return nil, fmt.Errorf("where: bad pattern at %d: %v", t.pos, err)
+			}
+		}
+	}
+	return cmp, nil
+}
+
+func (p *whereParser) operand() (whereNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString:
+		return &literalNode{t.text}, nil
+	case tokNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("where: bad number %q at %d", t.text, t.pos)
+		}
+		return &literalNode{n}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return &literalNode{true}, nil
+		case "false":
+			return &literalNode{false}, nil
+		case "null":
+			return &literalNode{nil}, nil
+		case "in", "contains":
+			return nil, fmt.Errorf("where: unexpected %q at %d", t.text, t.pos)
+		}
+		return &fieldNode{path: strings.Split(t.text, ".")}, nil
+	case tokOp:
+		switch t.text {
+		case "(":
+			inner, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			if !p.accept(")") {
+				return nil, p.errorf("expected )")
+			}
+			return inner, nil
+		case "[":
+			list := &listNode{}
+			for !p.accept("]") {
+				if len(list.items) > 0 && !p.accept(",") {
+					return nil, p.errorf("expected , or ]")
+				}
+				item, err := p.operand()
+				if err != nil {
+					return nil, err
+				}
+				list.items = append(list.items, item)
+			}
+			return list, nil
+		}
+	}
+	return nil, fmt.Errorf("where: unexpected %s at %d", quoteTok(t), t.pos)
+}
+
+func quoteTok(t whereTok) string {
+	if t.kind == tokEOF {
+		return t.text
+	}
+	return strconv.Quote(t.text)
+}
+
+// Evaluation: values are string, float64, bool, []any or nil.
+
+type whereNode interface {
+	eval(records []any) (any, error)
+}
+
+type literalNode struct{ value any }
+
+func (n *literalNode) eval([]any) (any, error) { return n.value, nil }
+
+type listNode struct{ items []whereNode }
+
+func (n *listNode) eval(records []any) (any, error) {
+	values := make([]any, len(n.items))
+	for i, item := range n.items {
+		v, err := item.eval(records)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+type fieldNode struct{ path []string }
+
+func (n *fieldNode) eval(records []any) (any, error) {
+	for _, r := range records {
+		if v, ok := lookupField(reflect.ValueOf(r), n.path); ok {
+			return plainValue(v), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown field %q", strings.Join(n.path, "."))
+}
+
+// lookupField follows a dotted path through structs, string-keyed maps and
+// pointers, matching names case-insensitively with underscores ignored.
+func lookupField(v reflect.Value, path []string) (reflect.Value, bool) {
+	for _, name := range path {
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		want := strings.ReplaceAll(name, "_", "")
+		if v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String {
+			found := false
+			for _, key := range v.MapKeys() {
+				if strings.EqualFold(strings.ReplaceAll(key.String(), "_", ""), want) {
+					v, found = v.MapIndex(key), true
+					break
+				}
+			}
+			if !found {
+				return reflect.Value{}, false
+			}
+			continue
+		}
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+		v = v.FieldByNameFunc(func(field string) bool { return strings.EqualFold(field, want) })
+		if !v.IsValid() {
+			return reflect.Value{}, false
+		}
+	}
+	return v, true
+}
+
+// plainValue converts a field to an expression value.
+func plainValue(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Slice, reflect.Array:
+		values := make([]any, v.Len())
+		for i := range values {
+			values[i] = plainValue(v.Index(i))
+		}
+		return values
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return plainValue(v.Elem())
+	}
+	return v.Interface()
+}
+
+type notNode struct{ operand whereNode }
+
+func (n *notNode) eval(records []any) (any, error) {
+	v, err := n.operand.eval(records)
+	return !truthy(v), err
+}
+
+type logicNode struct {
+	op          string
+	left, right whereNode
+}
+
+func (n *logicNode) eval(records []any) (any, error) {
+	left, err := n.left.eval(records)
+	if err != nil {
+		return nil, err
+	}
+	if truthy(left) == (n.op == "||") {
+		return n.op == "||", nil
+	}
+	right, err := n.right.eval(records)
+	if err != nil {
+		return nil, err
+	}
+	return truthy(right), nil
+}
+
+type compareNode struct {
+	op          string
+	left, right whereNode
+	re          *regexp.Regexp // compiled literal pattern for =~ and !~
+}
+
+func (n *compareNode) eval(records []any) (any, error) {
+	left, err := n.left.eval(records)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(records)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==", "!=":
+		eq, err := equal(left, right)
+		return eq == (n.op == "=="), err
+	case "in":
+		list, ok := right.([]any)
+		if !ok {
+			return nil, fmt.Errorf("in needs a list, got %s", describe(right))
+		}
+		return containsValue(list, left), nil
+	case "contains":
+		switch l := left.(type) {
+		case []any:
+			return containsValue(l, right), nil
+		case string:
+			s, ok := right.(string)
+			if !ok {
+				return nil, fmt.Errorf("contains on a string needs a string, got %s", describe(right))
+			}
+			return strings.Contains(l, s), nil
+		}
+		return nil, fmt.Errorf("contains needs a list or string, got %s", describe(left))
+	case "=~", "!~":
+		s, ok := left.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s needs a string, got %s", n.op, describe(left))
+		}
+		re := n.re
+		if re == nil {
+			pattern, ok := right.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s needs a string pattern, got %s", n.op, describe(right))
+			}
+			if re, err = regexp.Compile(pattern); err != nil {
+				return nil, err
+			}
+		}
+		return re.MatchString(s) == (n.op == "=~"), nil
+	}
+	return order(n.op, left, right)
+}
+
+func truthy(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case float64:
+		return v != 0
+	case []any:
+		return len(v) > 0
+	}
+	return true
+}
+
+func describe(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case []any:
+		return "a list"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// equal compares two values of the same type; null equals only null.
+func equal(a, b any) (bool, error) {
+	if a == nil || b == nil {
+		return a == nil && b == nil, nil
+	}
+	if la, ok := a.([]any); ok {
+		lb, ok := b.([]any)
+		if !ok || len(la) != len(lb) {
+			return false, nil
+		}
+		for i := range la {
+			if eq, err := equal(la[i], lb[i]); err != nil || !eq {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false, fmt.Errorf("cannot compare %s with %s", describe(a), describe(b))
+	}
+	return a == b, nil
+}
+
+func containsValue(list []any, v any) bool {
+	for _, item := range list {
+		if eq, err := equal(item, v); err == nil && eq {
+			return true
+		}
+	}
+	return false
+}
+
+// order compares numbers or strings with < <= > >=.
+func order(op string, a, b any) (bool, error) {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false, fmt.Errorf("cannot compare %s with %s", describe(a), describe(b))
+	}
+	var c int
+	switch a := a.(type) {
+	case float64:
+		switch nb := b.(float64); {
+		case a < nb:
+			c = -1
+		case a > nb:
+			c = 1
+		}
+	case string:
+		c = strings.Compare(a, b.(string))
+	default:
+		return false, fmt.Errorf("%s needs numbers or strings, got %s", op, describe(a))
+	}
+	switch op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	}
+	return c >= 0, nil
+}
@@ -0,0 +1,105 @@
+// CRC: crc-Query.md | R133
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zot/minispec/internal/parser"
+)
+
+func TestWhereMatch(t *testing.T) {
+	req := parser.Requirement{ID: "R12", Text: "Login with email", Source: "specs/auth.md#login", Priority: "P1", Tags: []string{"security", "ui"}, Line: 40}
+	gap := parser.Gap{ID: "D3", Type: "D", Description: "missing view", HasCheckbox: true}
+
+	tests := []struct {
+		expr   string
+		record any
+		want   bool
+	}{
+		{`retired == false && source == "specs/auth.md#login"`, req, true},
+		{`retired`, req, false},
+		{`!retired && priority != "P2"`, req, true},
+		{`tags contains "security"`, req, true},
+		{`tags contains "perf" || text =~ "(?i)LOGIN"`, req, true},
+		{`text !~ "email"`, req, false},
+		{`text contains "email"`, req, true},
+		{`line >= 40 && line < 41`, req, true},
+		{`id in ["R1", "R12"]`, req, true},
+		{`status == ""`, req, true},
+		{`type in ["D", "C"] && !resolved`, gap, true},
+		{`has_checkbox && (type == "S" || type == "D")`, gap, true},
+		{`!(type == "D")`, gap, false},
+		{`description`, gap, true},
+	}
+	for _, tt := range tests {
+		w, err := ParseWhere(tt.expr)
+		if err != nil {
+			t.Errorf("ParseWhere(%q): %v", tt.expr, err)
+			continue
+		}
+		got, err := w.Match(tt.record)
+		if err != nil {
+			t.Errorf("Match(%q): %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestWhereNestedRecords(t *testing.T) {
+	art := parser.Artifact{DesignFile: "crc-Store.md"}
+	cf := parser.CodeFile{Path: "src/store.ts"}
+	w, err := ParseWhere(`checked == false && design_file == "crc-Store.md"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := w.Match(cf, art); err != nil || !ok {
+		t.Errorf("Match(code file, artifact) = %v, %v; want true", ok, err)
+	}
+}
+
+func TestWhereErrors(t *testing.T) {
+	for _, expr := range []string{`(id == "R1"`, `id ==`, `id = "R1"`, `"unterminated`, `text =~ "("`, `id == "R1" extra`} {
+		if _, err := ParseWhere(expr); err == nil {
+			t.Errorf("ParseWhere(%q): expected an error", expr)
+		}
+	}
+
+	req := parser.Requirement{ID: "R1"}
+	for expr, want := range map[string]string{
+		`idd == "R1"`:    `unknown field "idd"`,
+		`id > 3`:         "cannot compare a string with a number",
+		`id in "R1"`:     "in needs a list",
+		`retired =~ "x"`: "=~ needs a string",
+	} {
+		w, err := ParseWhere(expr)
+		if err != nil {
+			t.Errorf("ParseWhere(%q): %v", expr, err)
+			continue
+		}
+		if _, err := w.Match(req); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Match(%q) error = %v, want %q", expr, err, want)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	gaps := []parser.Gap{{ID: "D1", Type: "D"}, {ID: "C1", Type: "C", Resolved: true}, {ID: "O1", Type: "O"}}
+	w, _ := ParseWhere(`type in ["D", "C"] && !resolved`)
+	got, err := Filter(w, gaps)
+	if err != nil || len(got) != 1 || got[0].ID != "D1" {
+		t.Errorf("Filter = %v, %v; want [D1]", got, err)
+	}
+	if all, _ := Filter(nil, gaps); len(all) != 3 {
+		t.Errorf("Filter(nil) kept %d of 3", len(all))
+	}
+
+	w, _ = ParseWhere(`id =~ "^R1[0-9]$"`)
+	ids, err := FilterStrings(w, []string{"R1", "R12", "R120"}, "id")
+	if err != nil || len(ids) != 1 || ids[0] != "R12" {
+		t.Errorf("FilterStrings = %v, %v; want [R12]", ids, err)
+	}
+}
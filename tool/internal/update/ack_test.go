@@ -0,0 +1,34 @@
+// CRC: crc-Update.md | R126
+package update
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zot/minispec/internal/parser"
+)
+
+func TestAcknowledge(t *testing.T) {
+	u := newTestProject(t, map[string]string{
+		"design/requirements.md": "- **R1:** store contacts\n- **~~R2:~~** (Retired T1 — no replacement) old\n- **R3:** [P1] list contacts #ui\n",
+		"design/crc-Store.md":    "# Store\n**Requirements:** R1, R2, R3, R9\n",
+	})
+	ids, err := u.Acknowledge("crc-Store.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []string{"R1", "R3"}) {
+		t.Errorf("ids = %v", ids)
+	}
+	acks, err := u.Project.LoadAcks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"R1": parser.Fingerprint("store contacts"),
+		"R3": parser.Fingerprint("list contacts"),
+	}
+	if !reflect.DeepEqual(acks["crc-Store.md"], want) {
+		t.Errorf("acks = %v, want %v", acks["crc-Store.md"], want)
+	}
+}
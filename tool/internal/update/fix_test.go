@@ -0,0 +1,155 @@
+// CRC: crc-Update.md | R90, R93
+package update
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zot/minispec/internal/project"
+)
+
+func newTestProject(t *testing.T, files map[string]string) *Update {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return New(&project.Project{
+		RootPath:  root,
+		DesignDir: filepath.Join(root, "design"),
+		SrcDir:    filepath.Join(root, "src"),
+		Config:    project.DefaultConfig(),
+	})
+}
+
+func readFile(t *testing.T, u *Update, rel string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(u.Project.RootPath, rel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestTraceRefEdits(t *testing.T) {
+	u := newTestProject(t, map[string]string{
+		"src/a.go":  "// CRC: crc-A.md | R1\npackage a\n",
+		"src/b.md":  "<!-- CRC: crc-B.md -->\n# B\n",
+		"src/c.go":  "// CRC: crc-C.md | Seq: seq-x.md, seq-y.md | R2\n",
+		"src/d.go":  "// CRC: crc-D.md | Seq: seq-x.md\n",
+		"src/e.go":  "// CRC: crc-E.md\n",
+		"src/f.txt": "no comment here\n",
+		"src/g.go":  "// CRC: crc-G.md\r\npackage g\r\n",
+		"src/h.md":  "<!-- CRC: crc-B.md -->\r\n# H\r\n",
+	})
+	cases := []struct {
+		file, ref string
+		add       bool
+		want      string
+	}{
+		{"src/a.go", "crc-Z.md", true, "// CRC: crc-A.md, crc-Z.md | R1\npackage a\n"},
+		{"src/a.go", "seq-q.md", true, "// CRC: crc-A.md, crc-Z.md | Seq: seq-q.md | R1\npackage a\n"},
+		{"src/b.md", "seq-q.md", true, "<!-- CRC: crc-B.md | Seq: seq-q.md -->\n# B\n"},
+		{"src/c.go", "seq-x.md", false, "// CRC: crc-C.md | Seq: seq-y.md | R2\n"},
+		{"src/d.go", "seq-x.md", false, "// CRC: crc-D.md\n"},
+		// CRLF files keep their line endings
+		{"src/g.go", "crc-A.md", true, "// CRC: crc-G.md, crc-A.md\r\npackage g\r\n"},
+		{"src/g.go", "seq-q.md", true, "// CRC: crc-G.md, crc-A.md | Seq: seq-q.md\r\npackage g\r\n"},
+		{"src/g.go", "seq-q.md", false, "// CRC: crc-G.md, crc-A.md\r\npackage g\r\n"},
+		{"src/h.md", "crc-A.md", true, "<!-- CRC: crc-B.md, crc-A.md -->\r\n# H\r\n"},
+	}
+	for _, tc := range cases {
+		var err error
+		if tc.add {
+			err = u.AddTraceRef(tc.file, tc.ref)
+		} else {
+			err = u.RemoveTraceRef(tc.file, tc.ref)
+		}
+		if err != nil {
+			t.Fatalf("%s %s: %v", tc.file, tc.ref, err)
+		}
+		if got := readFile(t, u, tc.file); got != tc.want {
+			t.Errorf("%s %s:\ngot  %q\nwant %q", tc.file, tc.ref, got, tc.want)
+		}
+	}
+	if err := u.RemoveTraceRef("src/e.go", "crc-E.md"); err == nil {
+		t.Error("removing the only CRC ref should fail")
+	}
+	if err := u.AddTraceRef("src/f.txt", "crc-F.md"); err == nil {
+		t.Error("adding to a file without traceability should fail")
+	}
+}
+
+func TestArtifactCodeEdits(t *testing.T) {
+	for _, eol := range []string{"\n", "\r\n"} {
+		testArtifactCodeEdits(t, eol)
+	}
+}
+
+func testArtifactCodeEdits(t *testing.T, eol string) {
+	u := newTestProject(t, map[string]string{
+		"design/design.md": strings.ReplaceAll("# D\n\n## Artifacts\n- [x] crc-A.md → `src/a.go`\n- [ ] seq-x.md\n- crc-L.md\n  - [x] src/l.go\n\n## Gaps\n", "\n", eol),
+	})
+	steps := []struct {
+		design, code string
+		add          bool
+	}{
+		{"crc-A.md", "src/b.go", true},
+		{"seq-x.md", "src/a.go", true},
+		{"crc-L.md", "src/m.go", true},
+		{"crc-A.md", "src/a.go", false},
+		{"crc-L.md", "src/l.go", false},
+	}
+	for _, s := range steps {
+		var err error
+		if s.add {
+			err = u.AddArtifactCode(s.design, s.code)
+		} else {
+			err = u.RemoveArtifactCode(s.design, s.code)
+		}
+		if err != nil {
+			t.Fatalf("%q %s %s: %v", eol, s.design, s.code, err)
+		}
+	}
+	want := strings.ReplaceAll("# D\n\n## Artifacts\n- [x] crc-A.md → `src/b.go`\n- [ ] seq-x.md → `src/a.go`\n- crc-L.md\n  - [ ] src/m.go\n\n## Gaps\n", "\n", eol)
+	if got := readFile(t, u, "design/design.md"); got != want {
+		t.Errorf("%q: got\n%q\nwant\n%q", eol, got, want)
+	}
+}
+
+func TestCloseTraceComment(t *testing.T) {
+	u := newTestProject(t, map[string]string{
+		"web/index.html": "<!-- CRC: crc-App.md | R1  \n<div></div>\n",
+		"web/crlf.html":  "<!-- CRC: crc-App.md | R1\r\n<div></div>\r\n",
+		"src/a.go":       "// CRC: crc-A.md\n",
+	})
+	if err := u.CloseTraceComment("web/index.html", 1); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := readFile(t, u, "web/index.html"), "<!-- CRC: crc-App.md | R1 -->\n<div></div>\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	// twice: the second call finds the CRLF line already closed
+	for i := 0; i < 2; i++ {
+		if err := u.CloseTraceComment("web/crlf.html", 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := readFile(t, u, "web/crlf.html"), "<!-- CRC: crc-App.md | R1 -->\r\n<div></div>\r\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if err := u.CloseTraceComment("web/index.html", 2); err == nil {
+		t.Error("closing a non-traceability line should fail")
+	}
+	if err := u.CloseTraceComment("src/a.go", 1); err == nil {
+		t.Error("closing a file type without a closer should fail")
+	}
+}
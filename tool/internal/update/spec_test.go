@@ -0,0 +1,62 @@
+// CRC: crc-Update.md | R125
+package update
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zot/minispec/internal/parser"
+)
+
+func TestAcknowledgeSpec(t *testing.T) {
+	u := newTestProject(t, map[string]string{
+		"specs/auth.md": "# Auth\n\n## Password Reset\nmail a link\n\n## Login\nform\n",
+		"design/requirements.md": "## Feature: Reset\n**Source:** specs/auth.md#password-reset <!-- spec-hash: 000000000000 -->\n\n- **R1:** reset\n\n" +
+			"## Feature: Login\n**Source:** specs/auth.md#login\n\n- **R2:** login\n\n" +
+			"## Feature: Other\n**Source:** specs/other.md\n",
+	})
+
+	n, err := u.AcknowledgeSpec("specs/auth.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("acknowledged %d sources, want 2", n)
+	}
+	sources, err := parser.ParseFeatureSources(u.Project.RequirementsPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := parser.SpecFingerprint(filepath.Join(u.Project.RootPath, "specs/auth.md"), "password-reset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sources[0].Source != "specs/auth.md#password-reset" || sources[0].Fingerprint != want {
+		t.Errorf("reset source = %+v, want fingerprint %s", sources[0], want)
+	}
+	if sources[1].Fingerprint == "" || sources[2].Fingerprint != "" {
+		t.Errorf("sources = %+v", sources)
+	}
+	if !strings.Contains(readFile(t, u, "design/requirements.md"), "- **R1:** reset\n") {
+		t.Error("requirement lines changed")
+	}
+
+	if _, err := u.AcknowledgeSpec("specs/missing.md"); err == nil {
+		t.Error("acknowledging an uncited spec succeeded")
+	}
+}
+
+func TestAcknowledgeSpecCRLF(t *testing.T) {
+	u := newTestProject(t, map[string]string{
+		"specs/auth.md":          "# Auth\r\n\r\nlog in\r\n",
+		"design/requirements.md": "## Feature: Auth\r\n**Source:** specs/auth.md\r\n\r\n- **R1:** login\r\n",
+	})
+	if _, err := u.AcknowledgeSpec("specs/auth.md"); err != nil {
+		t.Fatal(err)
+	}
+	got := readFile(t, u, "design/requirements.md")
+	if strings.Count(got, "\r\n") != strings.Count(got, "\n") || !strings.Contains(got, " -->\r\n") {
+		t.Errorf("line endings changed: %q", got)
+	}
+}
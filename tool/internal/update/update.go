@@ -1,4 +1,4 @@
-// CRC: crc-Update.md | Seq: seq-update.md
+// CRC: crc-Update.md | Seq: seq-update.md | R90, R93, R125, R126
 package update
 
 import (
@@ -344,6 +344,78 @@ func (u *Update) rewriteRetiredLine(path string, req *parser.Requirement, tn, re
 	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644)
 }
 
+// AcknowledgeSpec records the current fingerprint of a spec on the feature
+// Source lines that cite it, once their requirements have been reconciled
+// with it. A path without an anchor matches every Source in that file
+// (anchored or not); with an anchor it matches only that section. Returns the
+// number of Source lines updated. R125
+func (u *Update) AcknowledgeSpec(path string) (int, error) {
+	reqsPath := u.Project.RequirementsPath()
+	sources, err := parser.ParseFeatureSources(reqsPath)
+	if err != nil {
+		return 0, err
+	}
+	content, err := os.ReadFile(reqsPath)
+	if err != nil {
+		return 0, err
+	}
+	lines := strings.Split(string(content), "\n")
+
+	count := 0
+	for _, fs := range sources {
+		file, anchor, _ := strings.Cut(fs.Source, "#")
+		if fs.Source != path && file != path {
+			continue
+		}
+		hash, err := parser.SpecFingerprint(filepath.Join(u.Project.RootPath, file), anchor)
+		if err != nil {
+			return 0, err
+		}
+		_, cr := trimCR(lines[fs.Line-1])
+		lines[fs.Line-1] = fmt.Sprintf("**Source:** %s <!-- spec-hash: %s -->%s", fs.Source, hash, cr)
+		count++
+	}
+	if count == 0 {
+		return 0, fmt.Errorf("no feature Source cites %s", path)
+	}
+	return count, os.WriteFile(reqsPath, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// Acknowledge records the current text fingerprint of each requirement a CRC
+// card lists, marking the card as reviewed against them. Returns the
+// requirement IDs recorded. R126
+func (u *Update) Acknowledge(crcFile string) ([]string, error) {
+	card, err := parser.ParseCRCCard(u.Project.DesignPath(crcFile))
+	if err != nil {
+		return nil, err
+	}
+	reqs, err := parser.ParseRequirements(u.Project.RequirementsPath())
+	if err != nil {
+		return nil, err
+	}
+	acks, err := u.Project.LoadAcks()
+	if err != nil {
+		return nil, err
+	}
+
+	text := make(map[string]string, len(reqs))
+	for _, r := range reqs {
+		if !r.Retired {
+			text[r.ID] = r.Text
+		}
+	}
+	seen := make(map[string]string)
+	var ids []string
+	for _, ref := range card.Requirements {
+		if t, ok := text[ref]; ok {
+			seen[ref] = parser.Fingerprint(t)
+			ids = append(ids, ref)
+		}
+	}
+	acks[filepath.Base(crcFile)] = seen
+	return ids, u.Project.SaveAcks(acks)
+}
+
 // MigrationComplete moves specs/migrations/<name>.md to
 // specs/migrations/complete/<NNN>-<name>.md with the next zero-padded
 // three-digit prefix. Returns the new path (relative to project root). R81
@@ -387,6 +459,257 @@ func (u *Update) MigrationComplete(name string) (string, error) {
 	return rel, nil
 }
 
+// FixTraceMismatch repairs one Artifacts/header disagreement (see
+// validate.TraceMismatch). trust names the side taken as correct: "design"
+// rewrites the code file's traceability header, "code" rewrites the design.md
+// Artifacts line. R90
+func (u *Update) FixTraceMismatch(codeFile, designFile, missingFrom, trust string) error {
+	switch {
+	case trust == "design" && missingFrom == "header":
+		return u.AddTraceRef(codeFile, designFile)
+	case trust == "design" && missingFrom == "artifacts":
+		return u.RemoveTraceRef(codeFile, designFile)
+	case trust == "code" && missingFrom == "header":
+		return u.RemoveArtifactCode(designFile, codeFile)
+	case trust == "code" && missingFrom == "artifacts":
+		return u.AddArtifactCode(designFile, codeFile)
+	}
+	return fmt.Errorf("cannot fix %s/%s mismatch trusting %q (use design or code)", codeFile, designFile, trust)
+}
+
+// traceLines reads a code file (relative to the project root) and returns its
+// lines with the traceability regex and closer for its extension.
+func (u *Update) traceLines(codeFile string) (string, []string, *regexp.Regexp, string, error) {
+	path := filepath.Join(u.Project.RootPath, codeFile)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, nil, "", err
+	}
+	ext := filepath.Ext(codeFile)
+	traceRe, err := parser.TraceabilityRegexp(u.Project.CommentPattern(ext))
+	if err != nil {
+		return "", nil, nil, "", err
+	}
+	return path, strings.Split(string(content), "\n"), traceRe, u.Project.CommentCloser(ext), nil
+}
+
+// sectionBody splits a CRC or Seq section of a traceability comment into its
+// refs text and the trailing whitespace/closer that must be preserved.
+func sectionBody(s, closer string) (body, tail string) {
+	body = strings.TrimRight(s, " \t")
+	if c := strings.TrimSpace(closer); c != "" && strings.HasSuffix(body, c) {
+		body = strings.TrimRight(strings.TrimSuffix(body, c), " \t")
+	}
+	return body, s[len(body):]
+}
+
+// splitList splits a comma-separated list into trimmed, non-empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// AddTraceRef adds a crc-*.md or seq-*.md ref to the first traceability
+// comment of a code file, creating the Seq section if needed. R90
+func (u *Update) AddTraceRef(codeFile, ref string) error {
+	path, lines, traceRe, closer, err := u.traceLines(codeFile)
+	if err != nil {
+		return err
+	}
+	seq := strings.HasPrefix(ref, "seq-")
+	for i := range lines {
+		line, cr := trimCR(lines[i])
+		m := traceRe.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		start, end := m[2], m[3]
+		if seq {
+			start, end = m[4], m[5]
+		}
+		if start < 0 {
+			crcBody, _ := sectionBody(line[m[2]:m[3]], closer)
+			at := m[2] + len(crcBody)
+			lines[i] = line[:at] + " | Seq: " + ref + line[at:] + cr
+		} else {
+			body, tail := sectionBody(line[start:end], closer)
+			lines[i] = line[:start] + body + ", " + ref + tail + line[end:] + cr
+		}
+		return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644)
+	}
+	return fmt.Errorf("no traceability comment found in %s", codeFile)
+}
+
+// RemoveTraceRef removes a crc-*.md or seq-*.md ref from every traceability
+// comment of a code file. An emptied Seq section is dropped; a comment whose
+// CRC section would become empty is an error. R90
+func (u *Update) RemoveTraceRef(codeFile, ref string) error {
+	path, lines, traceRe, closer, err := u.traceLines(codeFile)
+	if err != nil {
+		return err
+	}
+	seq := strings.HasPrefix(ref, "seq-")
+	for i := range lines {
+		line, cr := trimCR(lines[i])
+		m := traceRe.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		start, end := m[2], m[3]
+		if seq {
+			start, end = m[4], m[5]
+		}
+		if start < 0 {
+			continue
+		}
+		body, tail := sectionBody(line[start:end], closer)
+		items := splitList(body)
+		kept := slices.DeleteFunc(slices.Clone(items), func(s string) bool { return s == ref })
+		if len(kept) == len(items) {
+			continue
+		}
+		switch {
+		case len(kept) > 0:
+			lines[i] = line[:start] + strings.Join(kept, ", ") + tail + line[end:] + cr
+		case seq:
+			crcBody, _ := sectionBody(line[m[2]:m[3]], closer)
+			at := m[2] + len(crcBody)
+			lines[i] = line[:at] + tail + line[end:] + cr
+		default:
+			return fmt.Errorf("%s line %d: removing %s would leave no CRC refs", codeFile, i+1, ref)
+		}
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// CloseTraceComment appends the configured closer for the file's extension to
+// the traceability comment on the given line. R93
+func (u *Update) CloseTraceComment(codeFile string, line int) error {
+	path, lines, traceRe, closer, err := u.traceLines(codeFile)
+	if err != nil {
+		return err
+	}
+	if closer == "" {
+		return fmt.Errorf("no comment closer configured for %s", filepath.Ext(codeFile))
+	}
+	if line <= 0 || line > len(lines) || !traceRe.MatchString(lines[line-1]) {
+		return fmt.Errorf("%s line %d is not a traceability comment", codeFile, line)
+	}
+	text, cr := trimCR(lines[line-1])
+	if parser.HasCloser(text, closer) {
+		return nil
+	}
+	lines[line-1] = strings.TrimRight(text, " \t") + closer + cr
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// findArtifact returns the Artifacts entry for a design file.
+func findArtifact(artifacts []parser.Artifact, designFile string) (parser.Artifact, bool) {
+	for _, a := range artifacts {
+		if a.DesignFile == designFile {
+			return a, true
+		}
+	}
+	return parser.Artifact{}, false
+}
+
+// inlineArtifactLineRe splits an inline Artifacts line into its design part
+// and optional code list.
+var inlineArtifactLineRe = regexp.MustCompile(`^(- \[[ x]\] [^\s→]+\.md)(?:\s*→\s*(.+))?$`)
+
+// trimCR splits a line of a CRLF file into its text and the "\r" it ended
+// with, so an edited line keeps the file's line ending.
+func trimCR(line string) (text, cr string) {
+	if text, ok := strings.CutSuffix(line, "\r"); ok {
+		return text, "\r"
+	}
+	return line, ""
+}
+
+// AddArtifactCode lists a code file under a design file's Artifacts entry,
+// in either the inline or the legacy nested format. R90
+func (u *Update) AddArtifactCode(designFile, codeFile string) error {
+	path := u.Project.DesignMdPath()
+	artifacts, err := parser.ParseArtifacts(path)
+	if err != nil {
+		return err
+	}
+	art, ok := findArtifact(artifacts, designFile)
+	if !ok {
+		return fmt.Errorf("%s not found in Artifacts", designFile)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(content), "\n")
+	line, cr := trimCR(lines[art.Line-1])
+
+	if m := inlineArtifactLineRe.FindStringSubmatch(line); m != nil {
+		if m[2] == "" {
+			lines[art.Line-1] = m[1] + " → `" + codeFile + "`" + cr
+		} else if strings.Contains(m[2], "`") {
+			lines[art.Line-1] = line + ", `" + codeFile + "`" + cr
+		} else {
+			lines[art.Line-1] = line + ", " + codeFile + cr
+		}
+	} else {
+		at := art.Line
+		for _, cf := range art.CodeFiles {
+			at = max(at, cf.Line)
+		}
+		lines = slices.Insert(lines, at, "  - [ ] "+codeFile+cr)
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// RemoveArtifactCode drops a code file from a design file's Artifacts entry,
+// in either the inline or the legacy nested format. R90
+func (u *Update) RemoveArtifactCode(designFile, codeFile string) error {
+	path := u.Project.DesignMdPath()
+	artifacts, err := parser.ParseArtifacts(path)
+	if err != nil {
+		return err
+	}
+	art, ok := findArtifact(artifacts, designFile)
+	if !ok {
+		return fmt.Errorf("%s not found in Artifacts", designFile)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(content), "\n")
+	line, cr := trimCR(lines[art.Line-1])
+
+	if m := inlineArtifactLineRe.FindStringSubmatch(line); m != nil {
+		var kept []string
+		for _, item := range splitList(m[2]) {
+			if strings.Trim(item, "`") != codeFile {
+				kept = append(kept, item)
+			}
+		}
+		lines[art.Line-1] = m[1]
+		if len(kept) > 0 {
+			lines[art.Line-1] += " → " + strings.Join(kept, ", ")
+		}
+		lines[art.Line-1] += cr
+	} else {
+		for _, cf := range art.CodeFiles {
+			if cf.Path == codeFile {
+				lines = slices.Delete(lines, cf.Line-1, cf.Line)
+				break
+			}
+		}
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644)
+}
+
 // SortRequirements sorts a comma-separated list of requirements numerically
 func SortRequirements(reqs []string) []string {
 	sorted := make([]string, len(reqs))
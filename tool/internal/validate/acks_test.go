@@ -0,0 +1,37 @@
+// CRC: crc-Validate.md | R126
+package validate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zot/minispec/internal/parser"
+	"github.com/zot/minispec/internal/project"
+)
+
+func TestCheckRequirementAcks(t *testing.T) {
+	p := &project.Project{RootPath: t.TempDir(), Config: project.DefaultConfig()}
+	if err := p.SaveAcks(project.Acks{"crc-Store.md": {
+		"R1": parser.Fingerprint("store contacts"),
+		"R2": parser.Fingerprint("old wording"),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	reqs := []parser.Requirement{
+		{ID: "R1", Text: "store contacts"},
+		{ID: "R2", Text: "new wording"},
+		{ID: "R3", Text: "never acknowledged"},
+	}
+	cards := []parser.CRCCard{
+		{Path: "design/crc-Store.md", Requirements: []string{"R1", "R2", "R3"}},
+		{Path: "design/crc-View.md", Requirements: []string{"R2"}},
+	}
+	found, err := New(p).checkRequirementAcks(cards, reqs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{"crc-Store.md": {"R2"}}
+	if got := itemsByFile(sortFindings(found)); !reflect.DeepEqual(got, want) {
+		t.Errorf("changed = %v, want %v", got, want)
+	}
+}
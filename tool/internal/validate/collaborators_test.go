@@ -0,0 +1,42 @@
+// CRC: crc-Validate.md | R101, R102
+package validate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zot/minispec/internal/parser"
+	"github.com/zot/minispec/internal/project"
+)
+
+func TestCheckCollaborators(t *testing.T) {
+	v := New(&project.Project{Config: project.DefaultConfig()})
+	card := func(name string, cols ...string) parser.CRCCard {
+		c := parser.CRCCard{Path: "design/crc-" + name + ".md"}
+		for _, col := range cols {
+			c.Collaborators = append(c.Collaborators, parser.Collaborator{Name: col})
+		}
+		return c
+	}
+	cards := []parser.CRCCard{
+		card("Store", "View", "Contact", "User", "Cache"),
+		card("View", "Store"),
+		card("Contact", "Store"),
+		card("Log", "Store"),
+	}
+	seqs := []parser.Sequence{{Participants: []string{"User", "View", "Store"}}}
+
+	m := &Model{Project: v.Project, v: v, Cards: cards, Seqs: seqs}
+
+	if got, want := itemsByFile(sortFindings(checkUnknownCollaborators(m))), map[string][]string{"crc-Store.md": {"Cache"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unknown = %v, want %v", got, want)
+	}
+	if got, want := itemsByFile(sortFindings(checkAsymmetricCollaborators(m))), map[string][]string{"crc-Log.md": {"Store"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("asymmetric = %v, want %v", got, want)
+	}
+	// Contact/Store is mutual and reported once, from Contact; Log is one-way.
+	want := map[string][]string{"crc-Contact.md": {"Store"}, "crc-Log.md": {"Store"}}
+	if got := itemsByFile(sortFindings(checkCollaboratorsInSeqs(m))); !reflect.DeepEqual(got, want) {
+		t.Errorf("not in seqs = %v, want %v", got, want)
+	}
+}
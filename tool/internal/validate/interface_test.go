@@ -0,0 +1,50 @@
+// CRC: crc-Validate.md | R106
+package validate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zot/minispec/internal/parser"
+	"github.com/zot/minispec/internal/project"
+)
+
+func TestCheckInterfaces(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"store.ts": "export class Store {\n  constructor(private db: Db) {}\n  getAll(): Item[] { return []; }\n  add(a: Item, b: Item): void {}\n  reset(): void {}\n}\n",
+		"view.ts":  "export class View {\n  constructor(root: HTMLElement, store: Store) {}\n  render(): void {}\n}\n",
+	})
+	v := New(&project.Project{RootPath: root, Config: project.DefaultConfig()})
+	cards := []parser.CRCCard{{
+		Path: "design/crc-Store.md",
+		Interface: []parser.Signature{
+			{Name: "getAll"},
+			{Name: "add", MinArgs: 1, MaxArgs: 1},
+			{Name: "remove", MinArgs: 1, MaxArgs: 1},
+		},
+	}, {
+		// declares its constructor, with the wrong arity
+		Path: "design/crc-View.md",
+		Interface: []parser.Signature{
+			{Name: "constructor", MinArgs: 1, MaxArgs: 1},
+			{Name: "render"},
+		},
+	}}
+	artifacts := []parser.Artifact{
+		{DesignFile: "crc-Store.md", CodeFiles: []parser.CodeFile{{Path: "store.ts"}}},
+		{DesignFile: "crc-View.md", CodeFiles: []parser.CodeFile{{Path: "view.ts"}}},
+	}
+
+	m := &Model{Project: v.Project, v: v, Cards: cards, Artifacts: artifacts}
+
+	if got, want := itemsByFile(sortFindings(checkInterfaceNotInCode(m))), map[string][]string{"crc-Store.md": {"remove"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("not in code = %v, want %v", got, want)
+	}
+	if got, want := itemsByFile(sortFindings(checkUndeclaredMethods(m))), map[string][]string{"crc-Store.md": {"reset"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("undeclared = %v, want %v", got, want)
+	}
+	want := map[string][]string{"crc-Store.md": {"add (interface 1, code 2)"}, "crc-View.md": {"constructor (interface 1, code 2)"}}
+	if got := itemsByFile(sortFindings(checkInterfaceArity(m))); !reflect.DeepEqual(got, want) {
+		t.Errorf("arity = %v, want %v", got, want)
+	}
+}
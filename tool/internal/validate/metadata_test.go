@@ -0,0 +1,32 @@
+// CRC: crc-Validate.md | R118
+package validate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zot/minispec/internal/parser"
+	"github.com/zot/minispec/internal/project"
+)
+
+func TestInvalidReqMetadata(t *testing.T) {
+	cfg := project.DefaultConfig()
+	cfg.Priorities = []string{"P0", "P1"}
+	cfg.Statuses = []string{"done"}
+	cfg.Tags = []string{"security"}
+	v := New(&project.Project{Config: cfg})
+	reqs := []parser.Requirement{
+		{ID: "R1", Priority: "P1", Status: "deferred", Tags: []string{"security"}},
+		{ID: "R2", Priority: "P9", Status: "wip", Tags: []string{"security", "perf"}},
+		{ID: "R3", Status: "done"},
+	}
+	want := []string{"R2 priority P9", "R2 status wip", "R2 tag #perf"}
+	if got := v.invalidReqMetadata(reqs); !reflect.DeepEqual(got, want) {
+		t.Errorf("invalid = %v, want %v", got, want)
+	}
+
+	v = New(&project.Project{Config: project.DefaultConfig()})
+	if got := v.invalidReqMetadata(reqs); got != nil {
+		t.Errorf("no allow-lists: invalid = %v", got)
+	}
+}
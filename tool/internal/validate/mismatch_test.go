@@ -0,0 +1,66 @@
+// CRC: crc-Validate.md | R89, R91
+package validate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zot/minispec/internal/parser"
+)
+
+func TestArtifactTraceMismatches(t *testing.T) {
+	artifacts := []parser.Artifact{
+		{DesignFile: "crc-A.md", CodeFiles: []parser.CodeFile{{Path: "src/a.ts"}, {Path: "src/b.ts"}}},
+		{DesignFile: "crc-B.md", CodeFiles: []parser.CodeFile{{Path: "src/b.ts"}}},
+		{DesignFile: "seq-x.md", CodeFiles: []parser.CodeFile{{Path: "src/a.ts"}}},
+		{DesignFile: "test-A.md", CodeFiles: []parser.CodeFile{{Path: "src/a.ts"}}},
+	}
+	traces := map[string]parser.Traceability{
+		"src/a.ts": {CRCRefs: []string{"crc-A.md"}, SeqRefs: []string{"seq-x.md"}},
+		"src/b.ts": {CRCRefs: []string{"crc-B.md", "crc-C.md"}},
+	}
+	// The fixer reads the mismatches back from the rule's findings.
+	mismatches := func() []TraceMismatch {
+		var found []Finding
+		for _, mm := range artifactTraceMismatches(artifacts, traces) {
+			found = append(found, mm.finding())
+		}
+		r := &ValidationResult{Findings: map[string][]Finding{"artifact-trace-mismatch": sortFindings(found)}}
+		return r.TraceMismatches()
+	}
+
+	// crc-C.md has no Artifacts entry, so it is left to UnlistedDesignFiles.
+	want := []TraceMismatch{
+		{CodeFile: "src/b.ts", DesignFile: "crc-A.md", MissingFrom: "header"},
+	}
+	if got := mismatches(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	traces["src/a.ts"] = parser.Traceability{CRCRefs: []string{"crc-B.md"}}
+	want = []TraceMismatch{
+		{CodeFile: "src/a.ts", DesignFile: "crc-A.md", MissingFrom: "header"},
+		{CodeFile: "src/a.ts", DesignFile: "crc-B.md", MissingFrom: "artifacts"},
+		{CodeFile: "src/a.ts", DesignFile: "seq-x.md", MissingFrom: "header"},
+		{CodeFile: "src/b.ts", DesignFile: "crc-A.md", MissingFrom: "header"},
+	}
+	if got := mismatches(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestReqRefsNotInCRC(t *testing.T) {
+	cardReqs := map[string]map[string]bool{
+		"crc-Store.md": {"R1": true, "R2": true},
+		"crc-View.md":  {"R40": true},
+	}
+	trace := parser.Traceability{Comments: []parser.TraceComment{
+		{CRCRefs: []string{"crc-Store.md"}, ReqRefs: []string{"R1", "R40", "R9"}},
+		{CRCRefs: []string{"crc-Store.md", "crc-View.md"}, ReqRefs: []string{"R40"}},
+		{CRCRefs: []string{"crc-Missing.md"}, ReqRefs: []string{"R77"}},
+	}}
+	got := reqRefsNotInCRC(trace, cardReqs, map[string]bool{"R9": true})
+	if want := []string{"R40"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,360 @@
+// CRC: crc-Validate.md | Seq: seq-validate.md | R95, R137, R138
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zot/minispec/internal/parser"
+	"github.com/zot/minispec/internal/project"
+	"github.com/zot/minispec/internal/query"
+)
+
+// Model is the parsed project that rules check. It is loaded once per run
+// and shared by every rule; files that cannot be read, while loading it or
+// later by a rule, are recorded in ReadErrors. Inputs only some rules need
+// (code symbols, tests, markup, spec headings) are parsed the first time a
+// rule asks for them. R137
+type Model struct {
+	Project   *project.Project
+	Reqs      []parser.Requirement
+	Cards     []parser.CRCCard
+	Seqs      []parser.Sequence
+	Gaps      []parser.Gap
+	Artifacts []parser.Artifact
+	Headers   map[string]parser.Traceability // code path -> traceability of each readable, non-binary code file
+	Absent    map[string]bool                // code paths listed in Artifacts that do not exist
+
+	ValidReqs   map[string]bool            // every Rn in requirements.md, retired included
+	RetiredReqs map[string]bool            // retired Rn
+	Approved    map[string]bool            // Rn covered by approved (A-type) gaps
+	CardReqs    map[string]map[string]bool // crc filename -> Rn it lists
+	ImplCovered map[string]bool            // Rn named by some code file's traceability comment
+
+	ReadErrors []Finding // files that could not be read, for read-errors
+
+	v         *Validate
+	symbols   map[string][]parser.Signature   // crc filename -> public symbols of its code files; nil when none was scanned
+	testScans map[int]testFiles               // Artifacts index -> test design cases and the tests in its code files
+	markups   map[int][]parser.Markup         // Artifacts index -> its existing CSS/HTML files
+	manifests map[string]*parser.Manifest     // manifest filename -> manifest; nil when missing or unreadable
+	layouts   map[string]*parser.UILayout     // ui filename -> layout; nil when missing or unreadable
+	headings  map[string][]parser.SpecHeading // spec path -> headings; nil when unreadable
+}
+
+// loadModel parses the project's requirements, design files and code file
+// headers.
+func (v *Validate) loadModel() (*Model, error) {
+	m := &Model{Project: v.Project, v: v}
+	var err error
+	if m.Reqs, err = v.Query.Requirements(); err != nil {
+		return nil, fmt.Errorf("requirements.md: %w", err)
+	}
+	m.ValidReqs = make(map[string]bool)
+	m.RetiredReqs = make(map[string]bool)
+	for _, r := range m.Reqs {
+		m.ValidReqs[r.ID] = true
+		if r.Retired {
+			m.RetiredReqs[r.ID] = true
+		}
+	}
+	if m.Cards, err = v.parseAllCRCCards(m); err != nil {
+		return nil, err
+	}
+	if m.Seqs, err = v.parseSequences(m); err != nil {
+		return nil, err
+	}
+	if m.Gaps, err = v.Query.Gaps(); err != nil {
+		return nil, fmt.Errorf("design.md Gaps: %w", err)
+	}
+	m.Approved = approvedGapReqs(m.Gaps)
+	if m.Artifacts, err = v.Query.Artifacts(); err != nil {
+		return nil, fmt.Errorf("design.md Artifacts: %w", err)
+	}
+
+	m.CardReqs = make(map[string]map[string]bool)
+	for _, c := range m.Cards {
+		set := make(map[string]bool, len(c.Requirements))
+		for _, ref := range c.Requirements {
+			set[ref] = true
+		}
+		m.CardReqs[filepath.Base(c.Path)] = set
+	}
+
+	m.Headers = make(map[string]parser.Traceability)
+	m.Absent = make(map[string]bool)
+	m.ImplCovered = make(map[string]bool)
+	for _, art := range m.Artifacts {
+		for _, cf := range art.CodeFiles {
+			if _, ok := m.Headers[cf.Path]; ok || m.Absent[cf.Path] {
+				continue
+			}
+			fullPath := filepath.Join(v.Project.RootPath, cf.Path)
+			if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+				m.Absent[cf.Path] = true
+				continue
+			}
+			ext := filepath.Ext(cf.Path)
+			trace, err := parser.ParseTraceability(fullPath, v.Project.CommentPattern(ext), v.Project.CommentCloser(ext))
+			if errors.Is(err, parser.ErrBinary) {
+				continue
+			}
+			if err != nil {
+				m.readError(cf.Path, err)
+				continue
+			}
+			m.Headers[cf.Path] = trace
+			for _, ref := range trace.ReqRefs {
+				m.ImplCovered[ref] = true
+			}
+		}
+	}
+	return m, nil
+}
+
+// readError records a file that could not be read. R95
+func (m *Model) readError(name string, err error) {
+	m.ReadErrors = append(m.ReadErrors, Finding{File: name, Item: readIssue(name, err)})
+}
+
+// DesignCovered returns the Rn listed by a CRC card or an approved gap.
+func (m *Model) DesignCovered() map[string]bool {
+	covered := make(map[string]bool, len(m.Approved))
+	for id := range m.Approved {
+		covered[id] = true
+	}
+	for _, set := range m.CardReqs {
+		for id := range set {
+			covered[id] = true
+		}
+	}
+	return covered
+}
+
+// coverage counts the tracked requirements' design and implementation
+// coverage, as uncovered-requirements and missing-impl-coverage judge it.
+func (m *Model) coverage() Coverage {
+	var c Coverage
+	designed := m.DesignCovered()
+	for _, req := range m.Reqs {
+		if req.Retired || req.Deferred() {
+			continue
+		}
+		c.Tracked++
+		if designed[req.ID] {
+			c.Designed++
+		}
+		if m.Approved[req.ID] || m.ImplCovered[req.ID] {
+			c.Implemented++
+		}
+	}
+	c.Design = query.Percent(c.Designed, c.Tracked)
+	c.Impl = query.Percent(c.Implemented, c.Tracked)
+	return c
+}
+
+// Traces returns the headers of code files with at least one CRC ref.
+func (m *Model) Traces() map[string]parser.Traceability {
+	traces := make(map[string]parser.Traceability, len(m.Headers))
+	for path, trace := range m.Headers {
+		if len(trace.CRCRefs) > 0 {
+			traces[path] = trace
+		}
+	}
+	return traces
+}
+
+// codeFiles returns the code files the Artifacts lines of a design file
+// list.
+func (m *Model) codeFiles(design string) []parser.CodeFile {
+	var files []parser.CodeFile
+	for _, art := range m.Artifacts {
+		if art.DesignFile == design {
+			files = append(files, art.CodeFiles...)
+		}
+	}
+	return files
+}
+
+// cardSymbols returns the public functions and methods of a CRC card's code
+// files, and false when no scanner understands any of them. R106
+func (m *Model) cardSymbols(card string) ([]parser.Signature, bool) {
+	if sigs, ok := m.symbols[card]; ok {
+		return sigs, sigs != nil
+	}
+	if m.symbols == nil {
+		m.symbols = make(map[string][]parser.Signature)
+	}
+	var code []parser.Signature
+	scanned := false
+	for _, cf := range m.codeFiles(card) {
+		fullPath := filepath.Join(m.Project.RootPath, cf.Path)
+		if _, err := os.Stat(fullPath); err != nil {
+			continue
+		}
+		sigs, err := parser.ParseCodeSymbols(fullPath)
+		if errors.Is(err, parser.ErrNoScanner) || errors.Is(err, parser.ErrBinary) || errors.Is(err, parser.ErrUnparsable) {
+			continue
+		}
+		if err != nil {
+			m.readError(cf.Path, err)
+			continue
+		}
+		scanned = true
+		code = append(code, sigs...)
+	}
+	if scanned && code == nil {
+		code = []parser.Signature{}
+	}
+	m.symbols[card] = code
+	return code, scanned
+}
+
+// testFiles is a test design's cases and the tests found in its code files.
+type testFiles struct {
+	cases []parser.TestCase
+	found map[string][]parser.TestName // code file -> tests
+}
+
+// tests returns the cases of the test design on Artifacts line i and the
+// tests in its existing code files, and false when the design does not
+// exist yet, cannot be read or has no code files to match. R109
+func (m *Model) tests(i int) (testFiles, bool) {
+	if scan, ok := m.testScans[i]; ok {
+		return scan, len(scan.found) > 0
+	}
+	if m.testScans == nil {
+		m.testScans = make(map[int]testFiles)
+	}
+	var scan testFiles
+	defer func() { m.testScans[i] = scan }()
+	art := m.Artifacts[i]
+	cases, err := parser.ParseTestDesign(m.Project.DesignPath(art.DesignFile))
+	if os.IsNotExist(err) {
+		return scan, false // a test design not written yet has no cases to match
+	}
+	if err != nil {
+		m.readError(art.DesignFile, err)
+		return scan, false
+	}
+	found := make(map[string][]parser.TestName)
+	for _, cf := range art.CodeFiles {
+		fullPath := filepath.Join(m.Project.RootPath, cf.Path)
+		if _, err := os.Stat(fullPath); err != nil {
+			continue
+		}
+		names, err := parser.ParseTestNames(fullPath, m.Project.CommentPattern(filepath.Ext(cf.Path)))
+		if errors.Is(err, parser.ErrBinary) {
+			continue
+		}
+		if err != nil {
+			m.readError(cf.Path, err)
+			continue
+		}
+		found[cf.Path] = names
+	}
+	scan = testFiles{cases: cases, found: found}
+	return scan, len(found) > 0
+}
+
+// markup returns the existing CSS/HTML code files of Artifacts line i,
+// parsed. R113, R114
+func (m *Model) markup(i int) []parser.Markup {
+	if mk, ok := m.markups[i]; ok {
+		return mk
+	}
+	if m.markups == nil {
+		m.markups = make(map[int][]parser.Markup)
+	}
+	var out []parser.Markup
+	for _, cf := range m.Artifacts[i].CodeFiles {
+		switch strings.ToLower(filepath.Ext(cf.Path)) {
+		case ".css", ".html", ".htm":
+		default:
+			continue
+		}
+		fullPath := filepath.Join(m.Project.RootPath, cf.Path)
+		if _, err := os.Stat(fullPath); err != nil {
+			continue
+		}
+		mk, err := parser.ParseMarkup(fullPath)
+		if errors.Is(err, parser.ErrBinary) {
+			continue
+		}
+		if err != nil {
+			m.readError(cf.Path, err)
+			continue
+		}
+		mk.Path = cf.Path
+		out = append(out, mk)
+	}
+	m.markups[i] = out
+	return out
+}
+
+// manifest returns a manifest design, or nil when it does not exist or
+// cannot be read. R113, R114
+func (m *Model) manifest(design string) *parser.Manifest {
+	if mf, ok := m.manifests[design]; ok {
+		return mf
+	}
+	if m.manifests == nil {
+		m.manifests = make(map[string]*parser.Manifest)
+	}
+	mf, err := parser.ParseManifest(m.Project.DesignPath(design))
+	if err != nil && !os.IsNotExist(err) {
+		m.readError(design, err)
+	}
+	if err != nil {
+		m.manifests[design] = nil
+		return nil
+	}
+	m.manifests[design] = &mf
+	return &mf
+}
+
+// layout returns a ui layout design, or nil when it does not exist or cannot
+// be read. R113
+func (m *Model) layout(design string) *parser.UILayout {
+	if ui, ok := m.layouts[design]; ok {
+		return ui
+	}
+	if m.layouts == nil {
+		m.layouts = make(map[string]*parser.UILayout)
+	}
+	ui, err := parser.ParseUILayout(m.Project.DesignPath(design))
+	if err != nil && !os.IsNotExist(err) {
+		m.readError(design, err)
+	}
+	if err != nil {
+		m.layouts[design] = nil
+		return nil
+	}
+	m.layouts[design] = &ui
+	return &ui
+}
+
+// specHeadings returns the headings of an existing spec file, and false when
+// it cannot be read. R122
+func (m *Model) specHeadings(file string) ([]parser.SpecHeading, bool) {
+	if hs, ok := m.headings[file]; ok {
+		return hs, hs != nil
+	}
+	if m.headings == nil {
+		m.headings = make(map[string][]parser.SpecHeading)
+	}
+	hs, err := parser.ParseSpecHeadings(filepath.Join(m.Project.RootPath, file))
+	if err != nil {
+		m.readError(file, err)
+		m.headings[file] = nil
+		return nil, false
+	}
+	if hs == nil {
+		hs = []parser.SpecHeading{}
+	}
+	m.headings[file] = hs
+	return hs, true
+}
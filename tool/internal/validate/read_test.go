@@ -0,0 +1,29 @@
+// CRC: crc-Validate.md | R94, R109, R113, R114
+package validate
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/zot/minispec/internal/project"
+)
+
+func TestReadErrors(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"design/requirements.md": "# Requirements\n",
+		"design/design.md":       "# Design\n\n## Artifacts\n- [x] test-Logo.md → `logo_test.go`\n- [ ] test-Later.md → `later_test.go`\n- [x] manifest-ui.md → `styles.css`\n\n## Gaps\n",
+		"design/crc-Logo.md":     "\x89PNG\x00\x00# Logo\n",
+		"design/test-Logo.md":    "\x89PNG\x00\x00# Test Design: Logo\n",
+		"design/manifest-ui.md":  "\x89PNG\x00\x00# UI Manifest\n",
+	})
+	p := &project.Project{RootPath: root, DesignDir: filepath.Join(root, "design"), Config: project.DefaultConfig()}
+
+	r, err := New(p).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"crc-Logo.md (binary file)", "manifest-ui.md (binary file)", "test-Logo.md (binary file)"}; !reflect.DeepEqual(r.Items("read-errors"), want) {
+		t.Errorf("read errors = %q, want %q", r.Items("read-errors"), want)
+	}
+}
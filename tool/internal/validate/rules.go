@@ -0,0 +1,611 @@
+// CRC: crc-Validate.md | Seq: seq-validate.md | R84, R88, R89, R91, R92, R137, R138
+package validate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/zot/minispec/internal/project"
+)
+
+// Severity is how much a rule's findings matter: error, warning or info.
+// R137, R138
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Severities lists the severities from most to least severe.
+var Severities = []Severity{SeverityError, SeverityWarning, SeverityInfo}
+
+// ParseSeverity checks a configured or command-line severity.
+func ParseSeverity(s string) (Severity, error) {
+	sev := Severity(strings.ToLower(s))
+	if !slices.Contains(Severities, sev) {
+		return "", fmt.Errorf("%q is not error, warning or info", s)
+	}
+	return sev, nil
+}
+
+// AtLeast reports whether s is as severe as min or more.
+func (s Severity) AtLeast(min Severity) bool {
+	return slices.Index(Severities, s) <= slices.Index(Severities, min)
+}
+
+// Rule is one validation category. It checks the shared Model and returns
+// only its own findings, which the result keeps under its ID; its ID is
+// stable and names it in .minispec.yaml, JSON output and phase filters. R137
+type Rule interface {
+	ID() string // kebab-case, e.g. "uncovered-requirements"
+	Description() string
+	DefaultSeverity() Severity
+	Run(m *Model) ([]Finding, error)
+	Report(found []Finding) string // findings as one text line, "" when none
+}
+
+// Issue is one rule's findings of one severity in the text report. R137, R138
+type Issue struct {
+	Rule     string
+	Severity Severity
+	Text     string
+}
+
+// PathSeverity gives the findings of some rules (all when Rules is empty)
+// about files matching Glob a severity of their own. R138
+type PathSeverity struct {
+	Glob     string
+	Severity Severity
+	Rules    []string
+}
+
+// Registry is an ordered set of rules, some of which may be disabled or
+// given another severity. Rules report in registration order. R137, R138
+type Registry struct {
+	rules      []Rule
+	disabled   map[string]bool
+	severities map[string]Severity
+	paths      []PathSeverity
+}
+
+// NewRegistry returns a registry of the given rules, all enabled.
+func NewRegistry(rules ...Rule) (*Registry, error) {
+	reg := &Registry{disabled: make(map[string]bool), severities: make(map[string]Severity)}
+	for _, rule := range rules {
+		if err := reg.Register(rule); err != nil {
+			return nil, err
+		}
+	}
+	return reg, nil
+}
+
+// DefaultRegistry returns a registry of the built-in rules, all enabled.
+func DefaultRegistry() *Registry {
+	reg, err := NewRegistry(builtinRules()...)
+	if err != nil {
+		panic(err) // built-in IDs are unique
+	}
+	return reg
+}
+
+// Register appends a rule, reporting after the existing ones.
+func (reg *Registry) Register(rule Rule) error {
+	if reg.Rule(rule.ID()) != nil {
+		return fmt.Errorf("duplicate rule %s", rule.ID())
+	}
+	reg.rules = append(reg.rules, rule)
+	return nil
+}
+
+// Rules returns every registered rule in report order.
+func (reg *Registry) Rules() []Rule {
+	return reg.rules
+}
+
+// Rule returns the rule with the given ID, or nil.
+func (reg *Registry) Rule(id string) Rule {
+	for _, rule := range reg.rules {
+		if rule.ID() == id {
+			return rule
+		}
+	}
+	return nil
+}
+
+// Enabled reports whether the rule runs and reports.
+func (reg *Registry) Enabled(id string) bool {
+	return !reg.disabled[id]
+}
+
+// SetEnabled turns a rule on or off.
+func (reg *Registry) SetEnabled(id string, on bool) error {
+	if reg.Rule(id) == nil {
+		return fmt.Errorf("unknown rule %s", id)
+	}
+	reg.disabled[id] = !on
+	return nil
+}
+
+// Severity returns the rule's configured severity, or its default.
+func (reg *Registry) Severity(id string) Severity {
+	if sev, ok := reg.severities[id]; ok {
+		return sev
+	}
+	if rule := reg.Rule(id); rule != nil {
+		return rule.DefaultSeverity()
+	}
+	return SeverityError
+}
+
+// SetSeverity overrides a rule's default severity.
+func (reg *Registry) SetSeverity(id string, sev Severity) error {
+	if reg.Rule(id) == nil {
+		return fmt.Errorf("unknown rule %s", id)
+	}
+	reg.severities[id] = sev
+	return nil
+}
+
+// AddPathSeverity gives findings about files matching ps.Glob another
+// severity; later overrides win over earlier ones.
+func (reg *Registry) AddPathSeverity(ps PathSeverity) error {
+	if _, err := globRegexp(ps.Glob); err != nil {
+		return err
+	}
+	for _, id := range ps.Rules {
+		if reg.Rule(id) == nil {
+			return fmt.Errorf("unknown rule %s", id)
+		}
+	}
+	reg.paths = append(reg.paths, ps)
+	return nil
+}
+
+// PathSeverity returns the severity of a rule's finding about path.
+func (reg *Registry) PathSeverity(id, path string) Severity {
+	sev := reg.Severity(id)
+	if path == "" {
+		return sev
+	}
+	for _, ps := range reg.paths {
+		if (len(ps.Rules) == 0 || slices.Contains(ps.Rules, id)) && matchGlob(ps.Glob, path) {
+			sev = ps.Severity
+		}
+	}
+	return sev
+}
+
+// Configure applies the rules: and severities: sections of .minispec.yaml.
+// A rules: value is on or off (true and false are accepted too), or a
+// severity, which also turns the rule on. R137, R138
+func (reg *Registry) Configure(cfg project.Config) error {
+	ids := make([]string, 0, len(cfg.Rules))
+	for id := range cfg.Rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		on := true
+		switch setting := strings.ToLower(cfg.Rules[id]); setting {
+		case "on", "true":
+		case "off", "false":
+			on = false
+		default:
+			sev, err := ParseSeverity(setting)
+			if err != nil {
+				return fmt.Errorf("rule %s: %q is not on, off or a severity", id, cfg.Rules[id])
+			}
+			if err := reg.SetSeverity(id, sev); err != nil {
+				return err
+			}
+		}
+		if err := reg.SetEnabled(id, on); err != nil {
+			return err
+		}
+	}
+	for _, o := range cfg.Severities {
+		sev, err := ParseSeverity(o.Severity)
+		if err != nil {
+			return fmt.Errorf("severities %s: %w", o.Path, err)
+		}
+		if err := reg.AddPathSeverity(PathSeverity{Glob: o.Path, Severity: sev, Rules: o.Rules}); err != nil {
+			return fmt.Errorf("severities %s: %w", o.Path, err)
+		}
+	}
+	return nil
+}
+
+// Issues returns the findings of the enabled rules in report order. A rule
+// whose findings have several severities (through path overrides) yields
+// one issue per severity, most severe first.
+func (reg *Registry) Issues(r *ValidationResult) []Issue {
+	var issues []Issue
+	for _, rule := range reg.rules {
+		id := rule.ID()
+		if !reg.Enabled(id) {
+			continue
+		}
+		sevs := []Severity{reg.Severity(id)}
+		for _, ps := range reg.paths {
+			if (len(ps.Rules) == 0 || slices.Contains(ps.Rules, id)) && !slices.Contains(sevs, ps.Severity) {
+				sevs = append(sevs, ps.Severity)
+			}
+		}
+		found := r.Findings[id]
+		if len(sevs) == 1 {
+			if text := rule.Report(found); text != "" {
+				issues = append(issues, Issue{Rule: id, Severity: sevs[0], Text: text})
+			}
+			continue
+		}
+		for _, sev := range Severities {
+			if !slices.Contains(sevs, sev) {
+				continue
+			}
+			var part []Finding
+			for _, f := range found {
+				if reg.PathSeverity(id, f.File) == sev {
+					part = append(part, f)
+				}
+			}
+			if text := rule.Report(part); text != "" {
+				issues = append(issues, Issue{Rule: id, Severity: sev, Text: text})
+			}
+		}
+	}
+	return issues
+}
+
+// globRegexp compiles a path glob: * and ? stay within one path segment,
+// ** spans any number of them.
+func globRegexp(glob string) (*regexp.Regexp, error) {
+	if glob == "" {
+		return nil, fmt.Errorf("empty path glob")
+	}
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			sb.WriteString("[^/]*")
+		case glob[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
+// matchGlob reports whether path matches glob; invalid globs match nothing.
+func matchGlob(glob, path string) bool {
+	re, err := globRegexp(glob)
+	return err == nil && re.MatchString(path)
+}
+
+// check is a built-in rule: a label and the check that finds its findings.
+type check struct {
+	id, label, description string
+	severity               Severity
+	run                    func(m *Model) ([]Finding, error)
+	render                 func(items []string) string
+	byFile                 bool // report "file → items; ..." rather than one list of items
+}
+
+func (c *check) ID() string                      { return c.id }
+func (c *check) Description() string             { return c.description }
+func (c *check) DefaultSeverity() Severity       { return c.severity }
+func (c *check) Run(m *Model) ([]Finding, error) { return c.run(m) }
+
+func (c *check) Report(found []Finding) string {
+	if len(found) == 0 {
+		return ""
+	}
+	text := ""
+	if c.byFile {
+		text = formatFileMap(itemsByFile(found), c.render)
+	} else {
+		text = c.render(items(found))
+	}
+	if text == "" {
+		return ""
+	}
+	return c.label + ": " + text
+}
+
+// NewRule returns a rule for checks outside this package. Its findings are
+// about no file and reported as "label: finding, finding".
+func NewRule(id, label, description string, severity Severity, run func(m *Model) ([]string, error)) Rule {
+	return listCheck(id, label, severity, description, joinComma, func(m *Model) ([]Finding, error) {
+		found, err := run(m)
+		if err != nil {
+			return nil, err
+		}
+		return reqFindings(found), nil
+	})
+}
+
+// listCheck is a rule whose findings are reported as one list of items,
+// rendered with render.
+func listCheck(id, label string, severity Severity, description string, render func([]string) string,
+	run func(m *Model) ([]Finding, error)) *check {
+	return &check{id: id, label: label, description: description, severity: severity, run: run, render: render}
+}
+
+// fileCheck is a rule whose findings are reported per file, each file's
+// items rendered with render.
+func fileCheck(id, label string, severity Severity, description string, render func([]string) string,
+	run func(m *Model) ([]Finding, error)) *check {
+	c := listCheck(id, label, severity, description, render, run)
+	c.byFile = true
+	return c
+}
+
+// noError adapts a check that cannot fail.
+func noError(check func(m *Model) []Finding) func(m *Model) ([]Finding, error) {
+	return func(m *Model) ([]Finding, error) { return check(m), nil }
+}
+
+// reqFindings makes findings about no file of items such as Rn and gap IDs.
+func reqFindings(items []string) []Finding {
+	found := make([]Finding, len(items))
+	for i, item := range items {
+		found[i] = Finding{Item: item}
+	}
+	return found
+}
+
+// fileFindings makes findings of file names or paths, each about itself.
+func fileFindings(files []string) []Finding {
+	found := make([]Finding, len(files))
+	for i, file := range files {
+		found[i] = Finding{File: file, Item: file}
+	}
+	return found
+}
+
+// builtinRules returns the built-in rules in the order FormatText reports
+// them.
+func builtinRules() []Rule {
+	return []Rule{
+		listCheck(readErrorsRule, "read errors", SeverityError,
+			"Files that could not be read (recorded while loading the project and by other rules)", joinComma,
+			noError(func(m *Model) []Finding { return m.ReadErrors })),
+		listCheck("uncovered-requirements", "uncovered requirements", SeverityError,
+			"Active requirements listed by no CRC card or approved gap", FormatRanges,
+			noError(func(m *Model) []Finding {
+				covered := m.DesignCovered()
+				var found []Finding
+				for _, req := range m.Reqs {
+					if !req.Retired && !req.Deferred() && !covered[req.ID] {
+						found = append(found, Finding{Item: req.ID})
+					}
+				}
+				return found
+			})),
+		listCheck("missing-impl-coverage", "missing impl coverage", SeverityError,
+			"Active requirements named by no code file's traceability comment or approved gap", FormatRanges,
+			noError(func(m *Model) []Finding {
+				var found []Finding
+				for _, req := range m.Reqs {
+					if !req.Retired && !req.Deferred() && !m.Approved[req.ID] && !m.ImplCovered[req.ID] {
+						found = append(found, Finding{Item: req.ID})
+					}
+				}
+				return found
+			})),
+		listCheck("duplicate-requirements", "duplicate requirements", SeverityError,
+			"Requirement numbers used more than once", FormatRanges,
+			noError(func(m *Model) []Finding { return reqFindings(duplicateReqs(m.Reqs)) })),
+		listCheck("numbering-gaps", "numbering gaps", SeverityError,
+			"Requirement numbers missing from the sequence", FormatRanges,
+			noError(func(m *Model) []Finding { return reqFindings(numberingGaps(m.Reqs)) })),
+		listCheck("invalid-requirement-metadata", "invalid requirement metadata", SeverityError,
+			"Priorities, statuses and tags outside the .minispec.yaml allow-lists", joinComma,
+			noError(func(m *Model) []Finding { return reqFindings(m.v.invalidReqMetadata(m.Reqs)) })),
+		fileCheck("unknown-crc-refs", "unknown CRC refs", SeverityError,
+			"CRC card Requirements entries that are not in requirements.md", FormatRanges,
+			noError(func(m *Model) []Finding {
+				var found []Finding
+				for _, c := range m.Cards {
+					for _, ref := range c.Requirements {
+						if !m.ValidReqs[ref] {
+							found = append(found, Finding{File: filepath.Base(c.Path), Item: ref})
+						}
+					}
+				}
+				return found
+			})),
+		fileCheck("requirements-changed-since-design", "requirements changed since design", SeverityError,
+			"Card requirements whose text changed since the card was acknowledged", FormatRanges,
+			func(m *Model) ([]Finding, error) { return m.v.checkRequirementAcks(m.Cards, m.Reqs) }),
+		listCheck("missing-artifacts", "missing artifacts", SeverityError,
+			"Checked Artifacts code files that do not exist", joinComma,
+			noError(func(m *Model) []Finding {
+				var missing []string
+				for _, art := range m.Artifacts {
+					for _, cf := range art.CodeFiles {
+						if cf.Checked && m.Absent[cf.Path] {
+							missing = append(missing, cf.Path)
+						}
+					}
+				}
+				return fileFindings(missing)
+			})),
+		listCheck("missing-traceability", "missing traceability", SeverityError,
+			"Artifacts code files without a CRC traceability comment", joinComma,
+			noError(func(m *Model) []Finding {
+				var missing []string
+				for path, trace := range m.Headers {
+					if len(trace.CRCRefs) == 0 {
+						missing = append(missing, path)
+					}
+				}
+				return fileFindings(missing)
+			})),
+		listCheck("unclosed-trace-comments", "unclosed trace comments", SeverityError,
+			"Traceability comments missing their extension's comment closer", joinComma,
+			noError(func(m *Model) []Finding {
+				var found []Finding
+				for path, trace := range m.Headers {
+					for _, c := range trace.Comments {
+						if c.Unclosed {
+							found = append(found, Finding{File: path, Line: c.Line, Item: fmt.Sprintf("%s:%d", path, c.Line)})
+						}
+					}
+				}
+				return found
+			})),
+		fileCheck("missing-design-refs", "missing design refs", SeverityError,
+			"Traceability comment refs to design files or requirements that do not exist", joinComma,
+			noError(func(m *Model) []Finding {
+				var found []Finding
+				for path, trace := range m.Headers {
+					for _, refs := range [][]string{trace.CRCRefs, trace.SeqRefs} {
+						for _, ref := range refs {
+							if _, err := os.Stat(m.Project.DesignPath(ref)); os.IsNotExist(err) {
+								found = append(found, Finding{File: path, Item: ref})
+							}
+						}
+					}
+					for _, ref := range trace.ReqRefs {
+						if !m.ValidReqs[ref] {
+							found = append(found, Finding{File: path, Item: ref})
+						}
+					}
+				}
+				return found
+			})),
+		fileCheck("requirement-refs-not-in-crc", "requirement refs not in CRC", SeverityError,
+			"Inline Rn refs that none of the same comment's CRC cards list", FormatRanges,
+			noError(func(m *Model) []Finding {
+				var found []Finding
+				for path, trace := range m.Headers {
+					for _, ref := range reqRefsNotInCRC(trace, m.CardReqs, m.Approved) {
+						if m.ValidReqs[ref] && !m.RetiredReqs[ref] {
+							found = append(found, Finding{File: path, Item: ref})
+						}
+					}
+				}
+				return found
+			})),
+		listCheck("unlisted-design-files", "unlisted design files", SeverityError,
+			"Design files in design/ that the Artifacts section does not list", joinComma,
+			noError(func(m *Model) []Finding { return fileFindings(m.v.unlistedDesignFiles(m.Artifacts)) })),
+		listCheck("missing-spec-sources", "missing spec sources", SeverityError,
+			"Requirement Source spec files that do not exist", joinComma, noError(checkSpecSources)),
+		listCheck("missing-spec-anchors", "missing spec anchors", SeverityError,
+			"Requirement Source anchors that name no heading of their spec", joinComma, noError(checkSpecAnchors)),
+		listCheck("stale-requirement-sources", "stale requirement sources", SeverityError,
+			"Fingerprinted feature Sources whose spec changed since acknowledged", joinComma, checkSpecFingerprints),
+		fileCheck("crc-sequences-not-found", "CRC sequences not found", SeverityError,
+			"CRC card Sequences entries that do not exist in design/", joinComma,
+			noError(func(m *Model) []Finding {
+				var found []Finding
+				for _, c := range m.Cards {
+					for _, seq := range c.Sequences {
+						if _, err := os.Stat(m.Project.DesignPath(seq)); os.IsNotExist(err) {
+							found = append(found, Finding{File: filepath.Base(c.Path), Item: seq})
+						}
+					}
+				}
+				return found
+			})),
+		fileCheck("unknown-sequence-participants", "unknown sequence participants", SeverityError,
+			"Sequence participants with no CRC card that are not externals", joinComma, noError(checkSeqParticipants)),
+		fileCheck("sequence-methods-not-in-interface", "sequence methods not in interface", SeverityError,
+			"Methods called in sequences that the target card's Interface does not declare", joinComma, noError(checkSeqMethods)),
+		fileCheck("unknown-collaborators", "unknown collaborators", SeverityError,
+			"Collaborators with no CRC card that are not externals", joinComma, noError(checkUnknownCollaborators)),
+		fileCheck("asymmetric-collaborators", "asymmetric collaborators", SeverityError,
+			"Card collaborators that do not list the card back", joinComma, noError(checkAsymmetricCollaborators)),
+		fileCheck("collaborators-in-no-sequence", "collaborators in no sequence", SeverityWarning,
+			"Collaborating cards that share no sequence diagram", joinComma, noError(checkCollaboratorsInSeqs)),
+		fileCheck("interface-methods-not-in-code", "interface methods not in code", SeverityError,
+			"Interface declarations missing from the card's code files", joinComma, noError(checkInterfaceNotInCode)),
+		fileCheck("undeclared-public-methods", "undeclared public methods", SeverityError,
+			"Public code symbols missing from the card's Interface", joinComma, noError(checkUndeclaredMethods)),
+		fileCheck("interface-arity-mismatch", "interface arity mismatch", SeverityError,
+			"Interface declarations whose argument count differs from the code", joinComma, noError(checkInterfaceArity)),
+		fileCheck("designed-tests-not-found", "designed tests not found", SeverityError,
+			"Test design cases with no matching test in code", joinQuoted, noError(checkMissingTests)),
+		fileCheck("tests-without-design", "tests without design", SeverityWarning,
+			"Tests in code with no matching test design case", joinQuoted, noError(checkUndesignedTests)),
+		fileCheck("ui-elements-not-in-markup", "ui elements not in markup", SeverityError,
+			"Layout components, selectors and mode classes missing from the CSS/HTML", joinComma, noError(checkUIElements)),
+		fileCheck("manifest-colors-not-in-stylesheets", "manifest colors not in stylesheets", SeverityError,
+			"Manifest palette colors not used by the CSS/HTML", joinComma, noError(checkManifestColors)),
+		fileCheck("colors-not-in-manifest", "colors not in manifest", SeverityError,
+			"Colors hard-coded in CSS/HTML that no manifest lists", joinComma, noError(checkColorsInManifest)),
+		listCheck("crcs-without-requirements-field", "CRCs without Requirements field", SeverityError,
+			"CRC cards with no or an empty Requirements field", joinComma,
+			noError(func(m *Model) []Finding {
+				var orphans []string
+				for _, c := range m.Cards {
+					if len(c.Requirements) == 0 {
+						orphans = append(orphans, filepath.Base(c.Path))
+					}
+				}
+				return fileFindings(orphans)
+			})),
+		fileCheck("artifact-trace-mismatch", "artifact trace mismatch", SeverityError,
+			"Artifacts lines and code traceability headers that disagree", joinComma,
+			noError(func(m *Model) []Finding {
+				var found []Finding
+				for _, mm := range artifactTraceMismatches(m.Artifacts, m.Traces()) {
+					found = append(found, mm.finding())
+				}
+				return found
+			})),
+		listCheck("permanent-gaps-with-checkbox", "permanent gaps with checkbox", SeverityError,
+			"Approved (A) and retirement (T) gaps written with a checkbox", joinComma,
+			noError(func(m *Model) []Finding {
+				var ids []string
+				for _, g := range m.Gaps {
+					if g.HasCheckbox && (g.Type == "A" || g.Type == "T") {
+						ids = append(ids, g.ID)
+					}
+				}
+				return reqFindings(ids)
+			})),
+		listCheck("duplicate-gap-ids", "duplicate gap IDs", SeverityError,
+			"Gap IDs used more than once", joinComma,
+			noError(func(m *Model) []Finding {
+				var dups []string
+				seen := make(map[string]bool)
+				for _, g := range m.Gaps {
+					if seen[g.ID] {
+						dups = append(dups, g.ID)
+					}
+					seen[g.ID] = true
+				}
+				return reqFindings(dups)
+			})),
+	}
+}
+
+// registry returns the rules r was produced with; results built by hand use
+// the built-in rules.
+func (r *ValidationResult) registry() *Registry {
+	if r.rules != nil {
+		return r.rules
+	}
+	return defaultRules
+}
+
+// defaultRules reports results that were not produced by Run.
+var defaultRules = DefaultRegistry()
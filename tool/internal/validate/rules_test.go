@@ -0,0 +1,216 @@
+// CRC: crc-Validate.md | R137, R138
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/zot/minispec/internal/project"
+)
+
+// writeFiles writes files (paths relative to the root, directories created
+// as needed) into a new temporary project root and returns the root.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestBuiltinRuleIDs(t *testing.T) {
+	kebab := regexp.MustCompile(`^[a-z]+(-[a-z]+)*$`)
+	reg := DefaultRegistry()
+	if len(reg.Rules()) == 0 {
+		t.Fatal("no built-in rules")
+	}
+	for _, rule := range reg.Rules() {
+		if !kebab.MatchString(rule.ID()) {
+			t.Errorf("rule ID %q is not kebab-case", rule.ID())
+		}
+		if rule.Description() == "" || rule.DefaultSeverity() == "" {
+			t.Errorf("rule %s has no description or severity", rule.ID())
+		}
+	}
+	if _, err := NewRegistry(reg.Rule("read-errors"), reg.Rule("read-errors")); err == nil {
+		t.Error("duplicate rule IDs were accepted")
+	}
+}
+
+func TestFormatTextRuleOrder(t *testing.T) {
+	r := &ValidationResult{Findings: map[string][]Finding{
+		"duplicate-gap-ids":      {{Item: "D1"}},
+		"uncovered-requirements": {{Item: "R3"}, {Item: "R4"}},
+		"read-errors":            {{File: "src/x.ts", Item: "src/x.ts (permission denied)"}},
+		"unknown-collaborators":  {{File: "crc-Store.md", Item: "Cache"}},
+	}}
+	want := "issues:\n" +
+		"  read errors: src/x.ts (permission denied)\n" +
+		"  uncovered requirements: R3-4\n" +
+		"  unknown collaborators: crc-Store.md → Cache\n" +
+		"  duplicate gap IDs: D1\n" +
+		"\nphase: validate FAILED\n"
+	if got := r.FormatText(); got != want {
+		t.Errorf("FormatText() =\n%s\nwant\n%s", got, want)
+	}
+
+	if (&ValidationResult{}).FormatText() != "phase: validate OK\n" {
+		t.Error("an empty result does not pass")
+	}
+}
+
+func TestConfigureRules(t *testing.T) {
+	reg := DefaultRegistry()
+	if err := reg.Configure(project.Config{Rules: map[string]string{"numbering-gaps": "off", "duplicate-gap-ids": "false", "read-errors": "on"}}); err != nil {
+		t.Fatal(err)
+	}
+	if reg.Enabled("numbering-gaps") || reg.Enabled("duplicate-gap-ids") || !reg.Enabled("read-errors") {
+		t.Error("rules: settings not applied")
+	}
+	if err := reg.Configure(project.Config{Rules: map[string]string{"no-such-rule": "off"}}); err == nil {
+		t.Error("unknown rule accepted")
+	}
+	if err := reg.Configure(project.Config{Rules: map[string]string{"numbering-gaps": "sometimes"}}); err == nil {
+		t.Error("invalid setting accepted")
+	}
+}
+
+func TestRunRulesDisabled(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"design/requirements.md": "# Requirements\n\n## Feature: Core\n**Source:** specs/core.md\n\n- **R1:** first\n- **R3:** third\n",
+		"design/design.md":       "# Design\n\n## Artifacts\n\n## Gaps\n",
+	})
+	p := &project.Project{RootPath: root, DesignDir: filepath.Join(root, "design"), Config: project.DefaultConfig()}
+
+	reg := DefaultRegistry()
+	if err := reg.SetEnabled("numbering-gaps", false); err != nil {
+		t.Fatal(err)
+	}
+	extra := NewRule("no-first", "first requirement present", "Flags R1", SeverityInfo, func(m *Model) ([]string, error) {
+		return []string{m.Reqs[0].ID}, nil
+	})
+	if err := reg.Register(extra); err != nil {
+		t.Fatal(err)
+	}
+	off := NewRule("never-run", "never run", "Is disabled", SeverityError, func(m *Model) ([]string, error) {
+		t.Error("a disabled rule ran")
+		return nil, nil
+	})
+	if err := reg.Register(off); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.SetEnabled("never-run", false); err != nil {
+		t.Fatal(err)
+	}
+	r, err := New(p).RunRules(reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found, ok := r.Findings["numbering-gaps"]; ok {
+		t.Errorf("disabled rule reported %v", found)
+	}
+	if want := []string{"R1", "R3"}; !reflect.DeepEqual(r.Items("uncovered-requirements"), want) {
+		t.Errorf("uncovered = %v, want %v", r.Items("uncovered-requirements"), want)
+	}
+	last := r.Issues[len(r.Issues)-1]
+	if want := (Issue{Rule: "no-first", Severity: SeverityInfo, Text: "first requirement present: R1"}); last != want {
+		t.Errorf("last issue = %+v, want %+v", last, want)
+	}
+}
+
+func TestSeverities(t *testing.T) {
+	reg := DefaultRegistry()
+	cfg := project.Config{
+		Rules: map[string]string{"uncovered-requirements": "warning"},
+		Severities: []project.PathSeverity{
+			{Path: "legacy/**", Severity: "info"},
+			{Path: "legacy/keep/*.go", Severity: "error", Rules: []string{"missing-traceability"}},
+		},
+	}
+	if err := reg.Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	r := &ValidationResult{
+		Findings: map[string][]Finding{
+			"uncovered-requirements": {{Item: "R2"}},
+			"missing-traceability":   fileFindings([]string{"legacy/a/old.go", "legacy/keep/main.go", "src/new.go"}),
+		},
+		rules: reg,
+	}
+	want := []Issue{
+		{Rule: "uncovered-requirements", Severity: SeverityWarning, Text: "uncovered requirements: R2"},
+		{Rule: "missing-traceability", Severity: SeverityError, Text: "missing traceability: legacy/keep/main.go, src/new.go"},
+		{Rule: "missing-traceability", Severity: SeverityInfo, Text: "missing traceability: legacy/a/old.go"},
+	}
+	if got := reg.Issues(r); !reflect.DeepEqual(got, want) {
+		t.Errorf("Issues() = %+v, want %+v", got, want)
+	}
+	if len(r.Findings["missing-traceability"]) != 3 {
+		t.Error("Issues() changed the result")
+	}
+
+	delete(r.Findings, "missing-traceability")
+	wantText := "warnings:\n  uncovered requirements: R2\n\nphase: validate OK\n"
+	if got := r.FormatText(); got != wantText {
+		t.Errorf("FormatText() =\n%s\nwant\n%s", got, wantText)
+	}
+	r.SetThresholds(Thresholds{FailOn: SeverityWarning})
+	if r.Passed || !r.Failed() {
+		t.Error("a warning passed with --fail-on warning")
+	}
+
+	for _, bad := range []project.Config{
+		{Rules: map[string]string{"uncovered-requirements": "fatal"}},
+		{Severities: []project.PathSeverity{{Path: "src/**", Severity: "loud"}}},
+		{Severities: []project.PathSeverity{{Path: "src/**", Severity: "info", Rules: []string{"nope"}}}},
+	} {
+		if err := DefaultRegistry().Configure(bad); err == nil {
+			t.Errorf("Configure(%+v) accepted", bad)
+		}
+	}
+}
+
+func TestCoverageThresholds(t *testing.T) {
+	r := &ValidationResult{Coverage: Coverage{Tracked: 4, Designed: 4, Implemented: 3, Design: 100, Impl: 75}}
+	r.SetThresholds(Thresholds{MinDesignCoverage: 100, MinImplCoverage: 80})
+	if want := []string{"impl coverage 75% (3/4) below 80%"}; !reflect.DeepEqual(r.CoverageFailures, want) {
+		t.Errorf("CoverageFailures = %v, want %v", r.CoverageFailures, want)
+	}
+	want := "issues:\n  impl coverage 75% (3/4) below 80%\n\nphase: validate FAILED\n"
+	if got := r.FormatText(); got != want {
+		t.Errorf("FormatText() =\n%s\nwant\n%s", got, want)
+	}
+	r.SetThresholds(Thresholds{MinImplCoverage: 75})
+	if !r.Passed {
+		t.Error("coverage at the minimum failed")
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		glob, path string
+		want       bool
+	}{
+		{"legacy/**", "legacy/a/b.go", true},
+		{"**/*.css", "styles.css", true},
+		{"**/*.css", "web/css/site.css", true},
+		{"src/*.go", "src/a/b.go", false},
+		{"crc-Legacy*.md", "crc-LegacyStore.md", true},
+		{"src/?.go", "src/ab.go", false},
+	}
+	for _, tc := range cases {
+		if got := matchGlob(tc.glob, tc.path); got != tc.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tc.glob, tc.path, got, tc.want)
+		}
+	}
+}
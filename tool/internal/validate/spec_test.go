@@ -0,0 +1,28 @@
+// CRC: crc-Validate.md | R122
+package validate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zot/minispec/internal/parser"
+	"github.com/zot/minispec/internal/project"
+)
+
+func TestMissingSpecAnchors(t *testing.T) {
+	root := writeFiles(t, map[string]string{"specs/auth.md": "# Auth\n## Password Reset\n"})
+	v := New(&project.Project{RootPath: root, Config: project.DefaultConfig()})
+	reqs := []parser.Requirement{
+		{ID: "R1", Source: "specs/auth.md"},
+		{ID: "R2", Source: "specs/auth.md#password-reset"},
+		{ID: "R3", Source: "specs/auth.md#login"},
+		{ID: "R4", Source: "specs/gone.md#x"},
+	}
+	m := &Model{Project: v.Project, v: v, Reqs: reqs}
+	if missing := items(sortFindings(checkSpecSources(m))); !reflect.DeepEqual(missing, []string{"specs/gone.md"}) {
+		t.Errorf("missing = %v", missing)
+	}
+	if anchors := items(sortFindings(checkSpecAnchors(m))); !reflect.DeepEqual(anchors, []string{"specs/auth.md#login"}) {
+		t.Errorf("anchors = %v", anchors)
+	}
+}
@@ -0,0 +1,24 @@
+// CRC: crc-Validate.md | R109
+package validate
+
+import "testing"
+
+func TestTestNamesMatch(t *testing.T) {
+	tests := []struct {
+		design, code string
+		want         bool
+	}{
+		{"add() creates contact with unique id", "add() creates contact with unique id", true},
+		{"add() creates contact with unique id", "TestAddCreatesUniqueID", true},
+		{"ParseRequirements_ValidFile", "TestParseRequirements_ValidFile", true},
+		{"getById() returns undefined for missing id", "test_get_by_id_returns_undefined_for_missing_id", true},
+		{"subscribe() callback called on add", "subscribe() callback called on update", false},
+		{"update() persists changes", "delete() persists removal", false},
+		{"add() creates contact with unique id", "TestAdd", false},
+	}
+	for _, tt := range tests {
+		if got := testNamesMatch(tt.design, tt.code); got != tt.want {
+			t.Errorf("testNamesMatch(%q, %q) = %v, want %v", tt.design, tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// CRC: crc-Validate.md | R113, R114
+package validate
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/zot/minispec/internal/parser"
+	"github.com/zot/minispec/internal/project"
+)
+
+func TestCheckUI(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"design/manifest-ui.md": "## Theme\n\n**Light Mode**\n- Primary: #0066cc\n- Danger: #cc3333\n\n**Dark Mode** (class: .dark)\n- Primary: #4da6ff\n",
+		"design/ui-layout.md":   "## Components\n\n**Header**\n- Search: `#search`\n\n**Contact List**\n\n**Detail Panel**\n",
+		"styles.css":            ":root { --primary: #0066CC; }\n.dark { --primary: #4da6ff; }\n.contactList { color: #123456; }\n",
+		"index.html":            "<header id=\"top\"></header>\n",
+	})
+	v := New(&project.Project{RootPath: root, DesignDir: filepath.Join(root, "design"), Config: project.DefaultConfig()})
+	markup := []parser.CodeFile{{Path: "styles.css"}, {Path: "index.html"}}
+	artifacts := []parser.Artifact{
+		{DesignFile: "manifest-ui.md", CodeFiles: markup},
+		{DesignFile: "ui-layout.md", CodeFiles: markup},
+	}
+
+	m := &Model{Project: v.Project, v: v, Artifacts: artifacts}
+
+	if got, want := itemsByFile(sortFindings(checkUIElements(m))), map[string][]string{"ui-layout.md": {"#search", "Detail Panel"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ui elements = %v, want %v", got, want)
+	}
+	if got, want := itemsByFile(sortFindings(checkManifestColors(m))), map[string][]string{"manifest-ui.md": {"Light Mode / Danger (#cc3333)"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("manifest colors = %v, want %v", got, want)
+	}
+	if got, want := itemsByFile(sortFindings(checkColorsInManifest(m))), map[string][]string{"styles.css": {"#123456"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("colors = %v, want %v", got, want)
+	}
+}
@@ -1,36 +1,81 @@
-// CRC: crc-Validate.md | Seq: seq-validate.md | R68, R69, R70, R72, R76, R78, R84, R85, R86, R88
+// CRC: crc-Validate.md | Seq: seq-validate.md | R68, R69, R70, R72, R76, R78, R84, R85, R86, R88, R89, R91, R92, R95, R98, R99, R101, R102, R106, R109, R113, R114, R118, R119, R122, R124, R126
 package validate
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/zot/minispec/internal/parser"
 	"github.com/zot/minispec/internal/project"
 	"github.com/zot/minispec/internal/query"
 )
 
-// ValidationResult contains issues bucketed by category. R84
+// ValidationResult holds the findings of each enabled rule under its ID.
+// R84, R137
 type ValidationResult struct {
-	UncoveredReqs        []string            // R numbers
-	MissingImplCoverage  []string            // R numbers
-	DuplicateReqs        []string            // R numbers
-	ReqNumberingGaps     []string            // R numbers (missing in sequence)
-	UnknownCRCRefs       map[string][]string // file -> []Rn
-	MissingArtifacts     []string            // code paths
-	MissingTraceability  []string            // code paths
-	MissingDesignRefs    map[string][]string // code path -> []missing-ref
-	UnlistedDesignFiles  []string            // design filenames
-	MissingSpecSources   []string            // spec paths
-	MissingCRCSequences  map[string][]string // crc filename -> []seq-ref
-	CheckboxedPermanent  []string            // gap IDs
-	DuplicateGapIDs      []string            // gap IDs
-	OrphanCRCNoReqField  []string            // crc filenames
+	Findings map[string][]Finding // rule ID -> findings, sorted; rules with none are absent
+	Issues   []Issue              // enabled rules' findings with their rule IDs and severities, in report order (R137, R138)
+
+	Coverage         Coverage // design and implementation coverage of tracked requirements (R138)
+	CoverageFailures []string // coverage below a configured minimum (R138)
+	Passed           bool     // no issue at or above the failure severity and no coverage failure (R138)
+
+	rules      *Registry
+	thresholds Thresholds
+}
+
+// Finding is one thing a rule found: Item as the report shows it (an Rn, a
+// missing ref, "file:line") and the File it is about, with its Line when
+// known. File is "" for findings about no file; path severities match it.
+// R137, R138
+type Finding struct {
+	File string `json:",omitempty"`
+	Line int    `json:",omitempty"`
+	Item string
+}
+
+// Coverage counts the active, non-deferred requirements and how many of them
+// are covered by design (a CRC card or approved gap) and by implementation
+// (a code traceability comment or approved gap). R138
+type Coverage struct {
+	Tracked     int
+	Designed    int
+	Implemented int
+	Design      float64 // percent of Tracked
+	Impl        float64 // percent of Tracked
+}
+
+// Thresholds decide whether a result passes: it fails on any issue at least
+// as severe as FailOn (error when empty) and when coverage is below a
+// minimum percentage (0 for none). R138
+type Thresholds struct {
+	FailOn            Severity
+	MinDesignCoverage float64
+	MinImplCoverage   float64
+}
+
+// TraceMismatch is one disagreement between an Artifacts line and the
+// traceability header of one of its code files. MissingFrom names the side
+// that lacks the link: "header" when Artifacts maps CodeFile under DesignFile
+// but the header omits it, "artifacts" when the header names DesignFile but
+// its Artifacts line does not list CodeFile. R89
+type TraceMismatch struct {
+	CodeFile    string
+	DesignFile  string
+	MissingFrom string
+}
+
+// finding is the artifact-trace-mismatch finding for m.
+func (m TraceMismatch) finding() Finding {
+	return Finding{File: m.CodeFile, Item: fmt.Sprintf("%s (not in %s)", m.DesignFile, m.MissingFrom)}
 }
 
 // Validate runs all structural validations
@@ -44,190 +89,669 @@ func New(p *project.Project) *Validate {
 	return &Validate{Project: p, Query: query.New(p)}
 }
 
-// Run executes all validations and returns the bucketed result.
+// Run executes the built-in rules, minus those turned off by the rules:
+// section of .minispec.yaml, and returns their findings. R137
 func (v *Validate) Run() (*ValidationResult, error) {
-	result := &ValidationResult{
-		UnknownCRCRefs:      make(map[string][]string),
-		MissingDesignRefs:   make(map[string][]string),
-		MissingCRCSequences: make(map[string][]string),
+	reg, err := v.Registry()
+	if err != nil {
+		return nil, err
 	}
+	return v.RunRules(reg)
+}
 
-	reqs, err := v.Query.Requirements()
-	if err != nil {
-		return nil, fmt.Errorf("requirements.md: %w", err)
+// Registry returns the built-in rules configured by .minispec.yaml. R137
+func (v *Validate) Registry() (*Registry, error) {
+	reg := DefaultRegistry()
+	if err := reg.Configure(v.Project.Config); err != nil {
+		return nil, fmt.Errorf(".minispec.yaml: %w", err)
 	}
-	validReqs, retired, dups, numberingGaps := summarizeRequirements(reqs)
-	result.DuplicateReqs = dups
-	result.ReqNumberingGaps = numberingGaps
+	return reg, nil
+}
+
+// readErrorsRule names the rule that reports unreadable files. It runs after
+// the other rules, so that it also reports the files they could not read.
+const readErrorsRule = "read-errors"
 
-	cards, err := v.parseAllCRCCards()
+// RunRules loads the project model once and runs every enabled rule of reg
+// over it, keeping each rule's findings under its ID. Disabled rules do not
+// run. R137
+func (v *Validate) RunRules(reg *Registry) (*ValidationResult, error) {
+	m, err := v.loadModel()
 	if err != nil {
 		return nil, err
 	}
-	for _, c := range cards {
-		if len(c.Requirements) == 0 {
-			result.OrphanCRCNoReqField = append(result.OrphanCRCNoReqField, filepath.Base(c.Path))
-			continue
+	result := &ValidationResult{Findings: make(map[string][]Finding), rules: reg}
+	run := func(rule Rule) error {
+		found, err := rule.Run(m)
+		if err != nil {
+			return fmt.Errorf("rule %s: %w", rule.ID(), err)
 		}
-		for _, ref := range c.Requirements {
-			if !validReqs[ref] {
-				name := filepath.Base(c.Path)
-				result.UnknownCRCRefs[name] = append(result.UnknownCRCRefs[name], ref)
+		if len(found) > 0 {
+			result.Findings[rule.ID()] = sortFindings(found)
+		}
+		return nil
+	}
+	for _, rule := range reg.Rules() {
+		if reg.Enabled(rule.ID()) && rule.ID() != readErrorsRule {
+			if err := run(rule); err != nil {
+				return nil, err
 			}
 		}
 	}
+	if rule := reg.Rule(readErrorsRule); rule != nil && reg.Enabled(readErrorsRule) {
+		if err := run(rule); err != nil {
+			return nil, err
+		}
+	}
 
-	gaps, err := v.Query.Gaps()
-	if err != nil {
-		return nil, fmt.Errorf("design.md Gaps: %w", err)
+	result.Issues = reg.Issues(result)
+	result.Coverage = m.coverage()
+	result.SetThresholds(Thresholds{})
+	return result, nil
+}
+
+// SetThresholds sets the thresholds that decide Passed and the text status
+// line, recording coverage failures. R138
+func (r *ValidationResult) SetThresholds(t Thresholds) {
+	if t.FailOn == "" {
+		t.FailOn = SeverityError
 	}
-	approvedReqs := approvedGapReqs(gaps)
-	seenGap := make(map[string]bool)
-	for _, g := range gaps {
-		if seenGap[g.ID] {
-			result.DuplicateGapIDs = append(result.DuplicateGapIDs, g.ID)
+	r.thresholds = t
+	r.CoverageFailures = nil
+	if r.Coverage.Tracked > 0 {
+		if t.MinDesignCoverage > 0 && r.Coverage.Design < t.MinDesignCoverage {
+			r.CoverageFailures = append(r.CoverageFailures, fmt.Sprintf("design coverage %.0f%% (%d/%d) below %g%%",
+				r.Coverage.Design, r.Coverage.Designed, r.Coverage.Tracked, t.MinDesignCoverage))
 		}
-		seenGap[g.ID] = true
-		if g.HasCheckbox && (g.Type == "A" || g.Type == "T") {
-			result.CheckboxedPermanent = append(result.CheckboxedPermanent, g.ID)
+		if t.MinImplCoverage > 0 && r.Coverage.Impl < t.MinImplCoverage {
+			r.CoverageFailures = append(r.CoverageFailures, fmt.Sprintf("impl coverage %.0f%% (%d/%d) below %g%%",
+				r.Coverage.Impl, r.Coverage.Implemented, r.Coverage.Tracked, t.MinImplCoverage))
 		}
 	}
+	r.Passed = !r.Failed()
+}
 
-	covered := make(map[string]bool)
-	for _, c := range cards {
-		for _, ref := range c.Requirements {
-			covered[ref] = true
+// Failed reports an issue at or above the failure severity (error unless
+// SetThresholds says otherwise) or a coverage failure. R138
+func (r *ValidationResult) Failed() bool {
+	failOn := r.thresholds.FailOn
+	if failOn == "" {
+		failOn = SeverityError
+	}
+	for _, is := range r.registry().Issues(r) {
+		if is.Severity.AtLeast(failOn) {
+			return true
 		}
 	}
-	for id := range approvedReqs {
-		covered[id] = true
+	return len(r.CoverageFailures) > 0
+}
+
+// reqNumber is the number of an Rn ID, and false for other strings.
+func reqNumber(id string) (int, bool) {
+	if !strings.HasPrefix(id, "R") {
+		return 0, false
+	}
+	n, err := strconv.Atoi(id[1:])
+	return n, err == nil
+}
+
+// duplicateReqs returns the requirements whose number an earlier one uses.
+func duplicateReqs(reqs []parser.Requirement) []string {
+	seen := make(map[int]bool)
+	var dups []string
+	for _, r := range reqs {
+		n, _ := reqNumber(r.ID)
+		if seen[n] {
+			dups = append(dups, r.ID)
+		}
+		seen[n] = true
 	}
+	return dups
+}
+
+// numberingGaps returns the numbers missing from the requirements' sequence,
+// whatever order they are listed in.
+func numberingGaps(reqs []parser.Requirement) []string {
+	var nums []int
 	for _, r := range reqs {
-		if r.Retired || covered[r.ID] {
+		n, _ := reqNumber(r.ID)
+		nums = append(nums, n)
+	}
+	if len(nums) == 0 {
+		return nil
+	}
+	sort.Ints(nums)
+	var gaps []string
+	for missing := 1; missing < nums[0]; missing++ {
+		gaps = append(gaps, fmt.Sprintf("R%d", missing))
+	}
+	for i := 1; i < len(nums); i++ {
+		for missing := nums[i-1] + 1; missing < nums[i]; missing++ {
+			gaps = append(gaps, fmt.Sprintf("R%d", missing))
+		}
+	}
+	return gaps
+}
+
+// parseAllCRCCards walks design/crc-*.md and returns parsed cards. Cards that
+// cannot be read are recorded as read errors. R95
+func (v *Validate) parseAllCRCCards(m *Model) ([]parser.CRCCard, error) {
+	files, err := v.Project.GlobCRCCards()
+	if err != nil {
+		return nil, err
+	}
+	cards := make([]parser.CRCCard, 0, len(files))
+	for _, p := range files {
+		c, err := parser.ParseCRCCard(p)
+		if err != nil {
+			m.readError(filepath.Base(p), err)
 			continue
 		}
-		result.UncoveredReqs = append(result.UncoveredReqs, r.ID)
+		cards = append(cards, c)
 	}
+	return cards, nil
+}
 
-	artifacts, err := v.Query.Artifacts()
+// parseSequences parses every seq-*.md diagram. Diagrams that cannot be read
+// are recorded as read errors. R95
+func (v *Validate) parseSequences(m *Model) ([]parser.Sequence, error) {
+	files, err := v.Project.GlobSequences()
 	if err != nil {
-		return nil, fmt.Errorf("design.md Artifacts: %w", err)
+		return nil, err
+	}
+	var seqs []parser.Sequence
+	for _, path := range files {
+		seq, err := parser.ParseSequence(path)
+		if err != nil {
+			m.readError(filepath.Base(path), err)
+			continue
+		}
+		seqs = append(seqs, seq)
 	}
-	result.UnlistedDesignFiles = v.unlistedDesignFiles(artifacts)
-	result.MissingSpecSources = v.missingSpecSources(reqs)
+	return seqs, nil
+}
 
+// cardsByName indexes CRC cards by card name.
+func cardsByName(cards []parser.CRCCard) map[string]parser.CRCCard {
+	byName := make(map[string]parser.CRCCard, len(cards))
 	for _, c := range cards {
-		for _, seq := range c.Sequences {
-			if _, err := os.Stat(v.Project.DesignPath(seq)); os.IsNotExist(err) {
-				name := filepath.Base(c.Path)
-				result.MissingCRCSequences[name] = append(result.MissingCRCSequences[name], seq)
+		byName[parser.CardName(c.Path)] = c
+	}
+	return byName
+}
+
+// checkSeqParticipants reports sequence participants that are neither a CRC
+// card nor a configured external. R98
+func checkSeqParticipants(m *Model) []Finding {
+	byName := cardsByName(m.Cards)
+	var found []Finding
+	for _, seq := range m.Seqs {
+		name := filepath.Base(seq.Path)
+		for _, p := range seq.Participants {
+			if _, ok := byName[p]; !ok && !m.Project.IsExternal(p) {
+				found = append(found, Finding{File: name, Item: p})
 			}
 		}
 	}
+	return found
+}
 
-	implCovered := make(map[string]bool)
-	for _, art := range artifacts {
-		for _, cf := range art.CodeFiles {
-			fullPath := filepath.Join(v.Project.RootPath, cf.Path)
-			if _, err := os.Stat(fullPath); os.IsNotExist(err) {
-				if cf.Checked {
-					result.MissingArtifacts = append(result.MissingArtifacts, cf.Path)
-				}
+// checkSeqMethods reports methods called on a card with an Interface block
+// that the block does not declare. R99
+func checkSeqMethods(m *Model) []Finding {
+	byName := cardsByName(m.Cards)
+	var found []Finding
+	for _, seq := range m.Seqs {
+		name := filepath.Base(seq.Path)
+		for _, msg := range seq.Messages {
+			card, ok := byName[msg.To]
+			if msg.Method == "" || !ok || len(card.Interface) == 0 || findSignature(card.Interface, msg.Method) != nil {
 				continue
 			}
+			found = append(found, Finding{File: name, Item: msg.To + "." + msg.Method})
+		}
+	}
+	return found
+}
 
-			ext := filepath.Ext(cf.Path)
-			pattern := v.Project.CommentPattern(ext)
-			closer := v.Project.CommentCloser(ext)
-			trace, err := parser.ParseTraceability(fullPath, pattern, closer)
-			if err != nil {
-				continue
+// findSignature returns the signature named name, or nil.
+func findSignature(sigs []parser.Signature, name string) *parser.Signature {
+	for i := range sigs {
+		if sigs[i].Name == name {
+			return &sigs[i]
+		}
+	}
+	return nil
+}
+
+// formatArity renders an accepted argument count: "2", "1-2" or "1+".
+func formatArity(s parser.Signature) string {
+	switch {
+	case s.MaxArgs < 0:
+		return fmt.Sprintf("%d+", s.MinArgs)
+	case s.MaxArgs != s.MinArgs:
+		return fmt.Sprintf("%d-%d", s.MinArgs, s.MaxArgs)
+	}
+	return strconv.Itoa(s.MinArgs)
+}
+
+// interfaceCards calls check with each CRC card that has an Interface block
+// and the public symbols of its code files. Cards whose code files no
+// scanner understands are skipped. R106
+func interfaceCards(m *Model, check func(name string, iface, code []parser.Signature)) {
+	for _, card := range m.Cards {
+		if len(card.Interface) == 0 {
+			continue
+		}
+		name := filepath.Base(card.Path)
+		if code, ok := m.cardSymbols(name); ok {
+			check(name, card.Interface, code)
+		}
+	}
+}
+
+// checkInterfaceNotInCode reports Interface declarations that no code file
+// of the card defines. R106
+func checkInterfaceNotInCode(m *Model) []Finding {
+	var found []Finding
+	interfaceCards(m, func(name string, iface, code []parser.Signature) {
+		for _, want := range iface {
+			if findSignature(code, want.Name) == nil {
+				found = append(found, Finding{File: name, Item: want.Name})
 			}
-			if len(trace.CRCRefs) == 0 {
-				result.MissingTraceability = append(result.MissingTraceability, cf.Path)
+		}
+	})
+	return found
+}
+
+// checkInterfaceArity reports Interface declarations whose argument count
+// differs from the code's. R106
+func checkInterfaceArity(m *Model) []Finding {
+	var found []Finding
+	interfaceCards(m, func(name string, iface, code []parser.Signature) {
+		for _, want := range iface {
+			if got := findSignature(code, want.Name); got != nil && formatArity(want) != formatArity(*got) {
+				found = append(found, Finding{File: name,
+					Item: fmt.Sprintf("%s (interface %s, code %s)", want.Name, formatArity(want), formatArity(*got))})
 			}
+		}
+	})
+	return found
+}
 
-			// CRC and Seq refs must resolve to files in design/.
-			// dedupAndSortAll handles deduplication of the result list.
-			for _, ref := range trace.CRCRefs {
-				if _, err := os.Stat(v.Project.DesignPath(ref)); os.IsNotExist(err) {
-					result.MissingDesignRefs[cf.Path] = append(result.MissingDesignRefs[cf.Path], ref)
-				}
+// checkUndeclaredMethods reports public code symbols missing from the card's
+// Interface block. R106
+func checkUndeclaredMethods(m *Model) []Finding {
+	var found []Finding
+	interfaceCards(m, func(name string, iface, code []parser.Signature) {
+		for _, sig := range code {
+			// A card need not declare a constructor; when it does, its arity is checked.
+			if sig.Name != "constructor" && findSignature(iface, sig.Name) == nil {
+				found = append(found, Finding{File: name, Item: sig.Name})
+			}
+		}
+	})
+	return found
+}
+
+// testStopWords carry no meaning when matching test names.
+var testStopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "to": true, "of": true, "for": true,
+	"with": true, "and": true, "on": true, "in": true, "is": true, "should": true,
+}
+
+// splitWords splits a name into words at non-alphanumerics, camelCase humps
+// (getById -> get By Id), acronym ends (IDFor -> ID For) and digit runs.
+func splitWords(name string) []string {
+	var words []string
+	rs := []rune(name)
+	start := -1
+	for i, r := range rs {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(rs[start:i]))
+				start = -1
 			}
-			for _, ref := range trace.SeqRefs {
-				if _, err := os.Stat(v.Project.DesignPath(ref)); os.IsNotExist(err) {
-					result.MissingDesignRefs[cf.Path] = append(result.MissingDesignRefs[cf.Path], ref)
+			continue
+		}
+		if start >= 0 {
+			prev := rs[i-1]
+			lowerToUpper := unicode.IsLower(prev) && unicode.IsUpper(r)
+			acronymEnd := unicode.IsUpper(prev) && unicode.IsUpper(r) && i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			digitEdge := unicode.IsDigit(prev) != unicode.IsDigit(r)
+			if lowerToUpper || acronymEnd || digitEdge {
+				words = append(words, string(rs[start:i]))
+				start = i
+			}
+		} else {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(rs[start:]))
+	}
+	return words
+}
+
+// testWords normalizes a test name to its set of lowercase words, dropping a
+// leading "test" (TestAddItem, test_add_item) and stop words.
+func testWords(name string) map[string]bool {
+	words := make(map[string]bool)
+	for i, w := range splitWords(name) {
+		w = strings.ToLower(w)
+		if testStopWords[w] || (i == 0 && w == "test") {
+			continue
+		}
+		words[w] = true
+	}
+	return words
+}
+
+// testNamesMatch fuzzily matches a designed test with a test found in code:
+// the names match when one's words (at least two) all appear in the other,
+// or when 80% of their combined words are shared.
+func testNamesMatch(design, code string) bool {
+	a, b := testWords(design), testWords(code)
+	if len(a) == 0 || len(b) == 0 {
+		return false
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	if shared == len(a) && (len(a) >= 2 || len(a) == len(b)) {
+		return true
+	}
+	return float64(shared)/float64(len(a)+len(b)-shared) >= 0.8
+}
+
+// testDesigns calls check with each test design on an Artifacts line and
+// the tests found in its code files. Designs whose test files do not exist
+// yet are skipped, and designs that cannot be read are read errors. R109
+func testDesigns(m *Model, check func(design string, tests testFiles)) {
+	for i, art := range m.Artifacts {
+		if !strings.HasPrefix(art.DesignFile, "test-") {
+			continue
+		}
+		if tests, ok := m.tests(i); ok {
+			check(art.DesignFile, tests)
+		}
+	}
+}
+
+// checkMissingTests reports each test design case that matches no test in
+// its code files; manual cases are not expected in code. R109
+func checkMissingTests(m *Model) []Finding {
+	var found []Finding
+	testDesigns(m, func(design string, tests testFiles) {
+		for _, tc := range tests.cases {
+			if tc.Manual {
+				continue
+			}
+			matched := false
+			for _, names := range tests.found {
+				for _, n := range names {
+					if testNamesMatch(tc.Name, n.Name) {
+						matched = true
+						break
+					}
 				}
 			}
+			if !matched {
+				found = append(found, Finding{File: design, Item: tc.Name})
+			}
+		}
+	})
+	return found
+}
 
-			for _, ref := range trace.ReqRefs {
-				if !validReqs[ref] && !retired[ref] {
-					result.MissingDesignRefs[cf.Path] = append(result.MissingDesignRefs[cf.Path], ref)
+// checkUndesignedTests reports tests in code that match no non-manual case
+// of their test design. R109
+func checkUndesignedTests(m *Model) []Finding {
+	var found []Finding
+	testDesigns(m, func(design string, tests testFiles) {
+		for file, names := range tests.found {
+			for _, n := range names {
+				designed := false
+				for _, tc := range tests.cases {
+					if !tc.Manual && testNamesMatch(tc.Name, n.Name) {
+						designed = true
+						break
+					}
+				}
+				if !designed {
+					found = append(found, Finding{File: file, Item: n.Name})
 				}
-				implCovered[ref] = true
 			}
 		}
+	})
+	return found
+}
+
+// markupName normalizes a component name, class, id or tag for comparison:
+// "Contact List", "contact-list" and "contactList" all become "contact-list".
+func markupName(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "-"))
+}
+
+// markupDefs is what a design's CSS/HTML files define: class and id names,
+// the normalized names of classes, ids and tags, and the colors they use.
+type markupDefs struct {
+	classes, ids, names, colors map[string]bool
+}
+
+func defsOf(markup []parser.Markup) markupDefs {
+	d := markupDefs{classes: make(map[string]bool), ids: make(map[string]bool), names: make(map[string]bool), colors: make(map[string]bool)}
+	for _, mk := range markup {
+		for _, c := range mk.Classes {
+			d.classes[c] = true
+			d.names[markupName(c)] = true
+		}
+		for _, id := range mk.IDs {
+			d.ids[id] = true
+			d.names[markupName(id)] = true
+		}
+		for _, tag := range mk.Tags {
+			d.names[markupName(tag)] = true
+		}
+		for _, c := range mk.Colors {
+			d.colors[parser.NormalizeColor(c.Value)] = true
+		}
 	}
+	return d
+}
 
-	for _, r := range reqs {
-		if r.Retired || approvedReqs[r.ID] || implCovered[r.ID] {
-			continue
+// uiDesigns calls check with each manifest-*.md and ui-*.md design on an
+// Artifacts line and the existing CSS/HTML files that line lists.
+func uiDesigns(m *Model, check func(design string, isManifest bool, markup []parser.Markup)) {
+	for i, art := range m.Artifacts {
+		isManifest := strings.HasPrefix(art.DesignFile, "manifest-")
+		if isManifest || strings.HasPrefix(art.DesignFile, "ui-") {
+			check(art.DesignFile, isManifest, m.markup(i))
 		}
-		result.MissingImplCoverage = append(result.MissingImplCoverage, r.ID)
 	}
+}
 
-	dedupAndSortAll(result)
-	return result, nil
+// checkUIElements reports layout components, backticked selectors and
+// manifest mode classes that a design's CSS/HTML files do not define.
+// Designs with no existing CSS/HTML files are skipped. R113
+func checkUIElements(m *Model) []Finding {
+	var found []Finding
+	uiDesigns(m, func(design string, isManifest bool, markup []parser.Markup) {
+		missing := func(item string) {
+			found = append(found, Finding{File: design, Item: item})
+		}
+		defs := defsOf(markup)
+		if isManifest {
+			if mf := m.manifest(design); mf != nil && len(markup) > 0 {
+				for _, c := range mf.Classes {
+					if !defs.classes[c] {
+						missing("." + c)
+					}
+				}
+			}
+			return
+		}
+		ui := m.layout(design)
+		if ui == nil || len(markup) == 0 {
+			return
+		}
+		for _, c := range ui.Components {
+			if !defs.names[markupName(c.Name)] {
+				missing(c.Name)
+			}
+		}
+		for _, sel := range ui.Selectors {
+			if (sel[0] == '.' && !defs.classes[sel[1:]]) || (sel[0] == '#' && !defs.ids[sel[1:]]) {
+				missing(sel)
+			}
+		}
+	})
+	return found
 }
 
-// summarizeRequirements returns a set of valid Rn IDs (any), the subset that are
-// retired, the list of duplicates, and the list of missing numbers in sequence.
-func summarizeRequirements(reqs []parser.Requirement) (valid map[string]bool, retired map[string]bool, dups []string, numGaps []string) {
-	valid = make(map[string]bool)
-	retired = make(map[string]bool)
-	seen := make(map[int]bool)
-	var nums []int
-	for _, r := range reqs {
-		valid[r.ID] = true
-		if r.Retired {
-			retired[r.ID] = true
+// checkManifestColors reports manifest palette colors that the manifest's
+// CSS/HTML files do not use. R114
+func checkManifestColors(m *Model) []Finding {
+	var found []Finding
+	uiDesigns(m, func(design string, isManifest bool, markup []parser.Markup) {
+		if !isManifest {
+			return
 		}
-		n, _ := strconv.Atoi(strings.TrimPrefix(r.ID, "R"))
-		if seen[n] {
-			dups = append(dups, r.ID)
+		mf := m.manifest(design)
+		if mf == nil || len(markup) == 0 {
+			return
 		}
-		seen[n] = true
-		nums = append(nums, n)
+		defs := defsOf(markup)
+		for _, e := range mf.Colors() {
+			for _, c := range parser.FindColors(e.Value) {
+				if c = parser.NormalizeColor(c); !defs.colors[c] {
+					label := e.Key
+					if e.Group != "" {
+						label = e.Group + " / " + e.Key
+					}
+					found = append(found, Finding{File: design, Item: fmt.Sprintf("%s (%s)", label, c)})
+				}
+			}
+		}
+	})
+	return found
+}
+
+// checkColorsInManifest reports colors hard-coded in the ui and manifest
+// designs' CSS/HTML files that no manifest lists. Projects with no manifest
+// colors are skipped. R114
+func checkColorsInManifest(m *Model) []Finding {
+	palette := make(map[string]bool)
+	markupByPath := make(map[string]parser.Markup)
+	uiDesigns(m, func(design string, isManifest bool, markup []parser.Markup) {
+		for _, mk := range markup {
+			markupByPath[mk.Path] = mk
+		}
+		if !isManifest {
+			return
+		}
+		if mf := m.manifest(design); mf != nil {
+			for _, e := range mf.Colors() {
+				for _, c := range parser.FindColors(e.Value) {
+					palette[parser.NormalizeColor(c)] = true
+				}
+			}
+		}
+	})
+	if len(palette) == 0 {
+		return nil
 	}
-	if len(nums) > 0 {
-		sort.Ints(nums)
-		for missing := 1; missing < nums[0]; missing++ {
-			numGaps = append(numGaps, fmt.Sprintf("R%d", missing))
+	var found []Finding
+	for path, mk := range markupByPath {
+		for _, c := range mk.Colors {
+			if !palette[parser.NormalizeColor(c.Value)] {
+				found = append(found, Finding{File: path, Item: c.Value})
+			}
 		}
-		for i := 1; i < len(nums); i++ {
-			for missing := nums[i-1] + 1; missing < nums[i]; missing++ {
-				numGaps = append(numGaps, fmt.Sprintf("R%d", missing))
+	}
+	return found
+}
+
+// cardCollaborators calls check with each CRC card's file name and each of
+// its collaborators that is another card, with that card.
+func cardCollaborators(m *Model, check func(card parser.CRCCard, file string, other parser.CRCCard)) {
+	byName := cardsByName(m.Cards)
+	for _, card := range m.Cards {
+		name := parser.CardName(card.Path)
+		for _, c := range card.Collaborators {
+			if other, ok := byName[c.Name]; ok && c.Name != name {
+				check(card, filepath.Base(card.Path), other)
 			}
 		}
 	}
-	return
 }
 
-// parseAllCRCCards walks design/crc-*.md and returns parsed cards.
-func (v *Validate) parseAllCRCCards() ([]parser.CRCCard, error) {
-	files, err := v.Project.GlobCRCCards()
-	if err != nil {
-		return nil, err
+// listsCollaborator reports whether card lists name as a collaborator.
+func listsCollaborator(card parser.CRCCard, name string) bool {
+	for _, c := range card.Collaborators {
+		if c.Name == name {
+			return true
+		}
 	}
-	cards := make([]parser.CRCCard, 0, len(files))
-	for _, p := range files {
-		c, err := parser.ParseCRCCard(p)
-		if err != nil {
-			continue
+	return false
+}
+
+// checkUnknownCollaborators reports collaborators that are neither a card nor
+// an external. R101
+func checkUnknownCollaborators(m *Model) []Finding {
+	byName := cardsByName(m.Cards)
+	var found []Finding
+	for _, card := range m.Cards {
+		for _, c := range card.Collaborators {
+			if _, ok := byName[c.Name]; !ok && !m.Project.IsExternal(c.Name) {
+				found = append(found, Finding{File: filepath.Base(card.Path), Item: c.Name})
+			}
 		}
-		cards = append(cards, c)
 	}
-	return cards, nil
+	return found
+}
+
+// checkAsymmetricCollaborators reports card collaborators that do not list
+// the card back. R102
+func checkAsymmetricCollaborators(m *Model) []Finding {
+	var found []Finding
+	cardCollaborators(m, func(card parser.CRCCard, file string, other parser.CRCCard) {
+		if !listsCollaborator(other, parser.CardName(card.Path)) {
+			found = append(found, Finding{File: file, Item: parser.CardName(other.Path)})
+		}
+	})
+	return found
+}
+
+// checkCollaboratorsInSeqs reports collaborating cards that share no sequence
+// diagram, a mutual pair once, from the card that sorts first. Projects with
+// no diagrams are skipped. R102
+func checkCollaboratorsInSeqs(m *Model) []Finding {
+	if len(m.Seqs) == 0 {
+		return nil
+	}
+	together := make(map[[2]string]bool)
+	for _, seq := range m.Seqs {
+		for _, a := range seq.Participants {
+			for _, b := range seq.Participants {
+				together[[2]string{a, b}] = true
+			}
+		}
+	}
+	var found []Finding
+	cardCollaborators(m, func(card parser.CRCCard, file string, other parser.CRCCard) {
+		name, otherName := parser.CardName(card.Path), parser.CardName(other.Path)
+		if !together[[2]string{name, otherName}] && (name < otherName || !listsCollaborator(other, name)) {
+			found = append(found, Finding{File: file, Item: otherName})
+		}
+	})
+	return found
 }
 
 func (v *Validate) unlistedDesignFiles(artifacts []parser.Artifact) []string {
@@ -249,19 +773,215 @@ func (v *Validate) unlistedDesignFiles(artifacts []parser.Artifact) []string {
 	return unlisted
 }
 
-func (v *Validate) missingSpecSources(reqs []parser.Requirement) []string {
-	checked := make(map[string]bool)
-	var missing []string
+// invalidReqMetadata reports requirement priorities, statuses and tags that
+// the .minispec.yaml allow-lists do not contain. An empty list allows any
+// value. R118
+func (v *Validate) invalidReqMetadata(reqs []parser.Requirement) []string {
+	cfg := v.Project.Config
+	statuses := v.Project.AllowedStatuses()
+	var invalid []string
 	for _, r := range reqs {
-		if r.Source == "" || checked[r.Source] {
+		if r.Priority != "" && len(cfg.Priorities) > 0 && !slices.Contains(cfg.Priorities, r.Priority) {
+			invalid = append(invalid, fmt.Sprintf("%s priority %s", r.ID, r.Priority))
+		}
+		if r.Status != "" && statuses != nil && !slices.Contains(statuses, r.Status) {
+			invalid = append(invalid, fmt.Sprintf("%s status %s", r.ID, r.Status))
+		}
+		for _, tag := range r.Tags {
+			if len(cfg.Tags) > 0 && !slices.Contains(cfg.Tags, tag) {
+				invalid = append(invalid, fmt.Sprintf("%s tag #%s", r.ID, tag))
+			}
+		}
+	}
+	return invalid
+}
+
+// specExists reports whether a Source spec file exists.
+func specExists(m *Model, file string) bool {
+	_, err := os.Stat(filepath.Join(m.Project.RootPath, file))
+	return !os.IsNotExist(err)
+}
+
+// checkSpecSources reports the Source spec files that do not exist. R122
+func checkSpecSources(m *Model) []Finding {
+	var found []Finding
+	checked := make(map[string]bool)
+	for _, r := range m.Reqs {
+		file := r.SourceFile()
+		if r.Source == "" || checked[file] {
+			continue
+		}
+		checked[file] = true
+		if !specExists(m, file) {
+			found = append(found, Finding{File: file, Item: file})
+		}
+	}
+	return found
+}
+
+// checkSpecAnchors reports the Source anchors with no matching heading in
+// their spec file. Missing specs are left to checkSpecSources, and specs
+// that cannot be read are read errors. R122
+func checkSpecAnchors(m *Model) []Finding {
+	var found []Finding
+	checked := make(map[string]bool)
+	for _, r := range m.Reqs {
+		anchor := r.SourceAnchor()
+		if anchor == "" || checked[r.Source] {
 			continue
 		}
 		checked[r.Source] = true
-		if _, err := os.Stat(filepath.Join(v.Project.RootPath, r.Source)); os.IsNotExist(err) {
-			missing = append(missing, r.Source)
+		file := r.SourceFile()
+		if !specExists(m, file) {
+			continue
+		}
+		hs, ok := m.specHeadings(file)
+		if ok && !slices.ContainsFunc(hs, func(h parser.SpecHeading) bool { return h.Anchor == anchor }) {
+			found = append(found, Finding{File: file, Item: r.Source})
+		}
+	}
+	return found
+}
+
+// checkRequirementAcks reports, per CRC card, the requirements whose text
+// fingerprint differs from the one recorded when the card was last
+// acknowledged. Cards and requirements with no recorded fingerprint are not
+// tracked. R126
+func (v *Validate) checkRequirementAcks(cards []parser.CRCCard, reqs []parser.Requirement) ([]Finding, error) {
+	acks, err := v.Project.LoadAcks()
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]string, len(reqs))
+	for _, r := range reqs {
+		if !r.Retired {
+			current[r.ID] = parser.Fingerprint(r.Text)
+		}
+	}
+	var found []Finding
+	for _, c := range cards {
+		name := filepath.Base(c.Path)
+		for _, ref := range c.Requirements {
+			seen, ok := acks[name][ref]
+			if ok && current[ref] != "" && current[ref] != seen {
+				found = append(found, Finding{File: name, Item: ref})
+			}
+		}
+	}
+	return found, nil
+}
+
+// checkSpecFingerprints reports feature Sources whose recorded spec
+// fingerprint no longer matches the spec (or spec section). Sources without
+// a fingerprint are not tracked; missing specs and anchors are reported by
+// checkSpecSources and checkSpecAnchors, and specs that cannot be read are
+// read errors. R124
+func checkSpecFingerprints(m *Model) ([]Finding, error) {
+	sources, err := parser.ParseFeatureSources(m.Project.RequirementsPath())
+	if err != nil {
+		return nil, fmt.Errorf("requirements.md: %w", err)
+	}
+	var found []Finding
+	for _, fs := range sources {
+		if fs.Fingerprint == "" {
+			continue
+		}
+		file, anchor, _ := strings.Cut(fs.Source, "#")
+		current, err := parser.SpecFingerprint(filepath.Join(m.Project.RootPath, file), anchor)
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, parser.ErrNoHeading) {
+			continue
+		}
+		if err != nil {
+			m.readError(file, err)
+			continue
+		}
+		if current != fs.Fingerprint {
+			found = append(found, Finding{File: file, Item: fs.Source})
+		}
+	}
+	return found, nil
+}
+
+// readIssue formats a read failure for ReadErrors, dropping the absolute path
+// that os errors carry. R95
+func readIssue(name string, err error) string {
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		err = pathErr.Err
+	}
+	return fmt.Sprintf("%s (%v)", name, err)
+}
+
+// isTraceDesign reports whether a design file is one that code headers name
+// (CRC cards in the CRC: section, sequences in the Seq: section).
+func isTraceDesign(name string) bool {
+	return strings.HasPrefix(name, "crc-") || strings.HasPrefix(name, "seq-")
+}
+
+// artifactTraceMismatches compares each Artifacts line's CRC/Seq design file
+// with the traceability headers of its code files, in both directions. Files
+// without any traceability comment are left to MissingTraceability, and header
+// refs with no Artifacts entry are left to UnlistedDesignFiles. R89
+func artifactTraceMismatches(artifacts []parser.Artifact, traces map[string]parser.Traceability) []TraceMismatch {
+	listed := make(map[string]bool)            // design files with an Artifacts entry
+	mapped := make(map[string]map[string]bool) // code path -> design files listing it
+	for _, art := range artifacts {
+		if !isTraceDesign(art.DesignFile) {
+			continue
+		}
+		listed[art.DesignFile] = true
+		for _, cf := range art.CodeFiles {
+			if mapped[cf.Path] == nil {
+				mapped[cf.Path] = make(map[string]bool)
+			}
+			mapped[cf.Path][art.DesignFile] = true
+		}
+	}
+
+	var out []TraceMismatch
+	for path, trace := range traces {
+		header := make(map[string]bool)
+		for _, ref := range append(append([]string{}, trace.CRCRefs...), trace.SeqRefs...) {
+			header[ref] = true
+		}
+		for design := range mapped[path] {
+			if !header[design] {
+				out = append(out, TraceMismatch{CodeFile: path, DesignFile: design, MissingFrom: "header"})
+			}
+		}
+		for ref := range header {
+			if listed[ref] && !mapped[path][ref] {
+				out = append(out, TraceMismatch{CodeFile: path, DesignFile: ref, MissingFrom: "artifacts"})
+			}
 		}
 	}
-	return missing
+	return out
+}
+
+// reqRefsNotInCRC returns the inline Rn refs of each traceability comment
+// that none of the CRC cards named in that same comment list, skipping refs
+// covered by approved gaps. Comments naming no known card are skipped; those
+// refs are already reported as missing design refs. R91
+func reqRefsNotInCRC(trace parser.Traceability, cardReqs map[string]map[string]bool, approved map[string]bool) []string {
+	var out []string
+	for _, c := range trace.Comments {
+		var named []map[string]bool
+		for _, ref := range c.CRCRefs {
+			if reqs, ok := cardReqs[ref]; ok {
+				named = append(named, reqs)
+			}
+		}
+		if len(named) == 0 {
+			continue
+		}
+		for _, ref := range c.ReqRefs {
+			if approved[ref] || slices.ContainsFunc(named, func(reqs map[string]bool) bool { return reqs[ref] }) {
+				continue
+			}
+			out = append(out, ref)
+		}
+	}
+	return out
 }
 
 // approvedGapReqRe matches Rn or Rn-Rm in approved-gap descriptions.
@@ -289,59 +1009,70 @@ func approvedGapReqs(gaps []parser.Gap) map[string]bool {
 	return reqs
 }
 
-// dedupAndSortAll deduplicates and sorts every list field in the result.
-func dedupAndSortAll(r *ValidationResult) {
-	r.UncoveredReqs = dedupReqIDs(r.UncoveredReqs)
-	r.MissingImplCoverage = dedupReqIDs(r.MissingImplCoverage)
-	r.DuplicateReqs = dedupReqIDs(r.DuplicateReqs)
-	r.ReqNumberingGaps = dedupReqIDs(r.ReqNumberingGaps)
-	r.MissingArtifacts = dedupStrings(r.MissingArtifacts)
-	r.MissingTraceability = dedupStrings(r.MissingTraceability)
-	r.UnlistedDesignFiles = dedupStrings(r.UnlistedDesignFiles)
-	r.MissingSpecSources = dedupStrings(r.MissingSpecSources)
-	r.CheckboxedPermanent = dedupStrings(r.CheckboxedPermanent)
-	r.DuplicateGapIDs = dedupStrings(r.DuplicateGapIDs)
-	r.OrphanCRCNoReqField = dedupStrings(r.OrphanCRCNoReqField)
-	for k, v := range r.UnknownCRCRefs {
-		r.UnknownCRCRefs[k] = dedupReqIDs(v)
-	}
-	for k, v := range r.MissingDesignRefs {
-		r.MissingDesignRefs[k] = dedupStrings(v)
-	}
-	for k, v := range r.MissingCRCSequences {
-		r.MissingCRCSequences[k] = dedupStrings(v)
+// sortFindings drops repeated findings and sorts the rest by file, line and
+// item, Rn items by number.
+func sortFindings(found []Finding) []Finding {
+	seen := make(map[Finding]bool, len(found))
+	out := make([]Finding, 0, len(found))
+	for _, f := range found {
+		if !seen[f] {
+			seen[f] = true
+			out = append(out, f)
+		}
 	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		na, okA := reqNumber(a.Item)
+		nb, okB := reqNumber(b.Item)
+		if okA && okB {
+			return na < nb
+		}
+		return a.Item < b.Item
+	})
+	return out
 }
 
-func dedupStrings(in []string) []string {
-	seen := make(map[string]bool, len(in))
-	out := make([]string, 0, len(in))
-	for _, s := range in {
-		if seen[s] {
-			continue
-		}
-		seen[s] = true
-		out = append(out, s)
+// items returns the findings' items in order.
+func items(found []Finding) []string {
+	out := make([]string, len(found))
+	for i, f := range found {
+		out[i] = f.Item
 	}
-	sort.Strings(out)
 	return out
 }
 
-func dedupReqIDs(in []string) []string {
-	seen := make(map[int]bool, len(in))
-	nums := make([]int, 0, len(in))
-	for _, id := range in {
-		n, err := strconv.Atoi(strings.TrimPrefix(id, "R"))
-		if err != nil || seen[n] {
-			continue
-		}
-		seen[n] = true
-		nums = append(nums, n)
+// itemsByFile groups the findings' items by file.
+func itemsByFile(found []Finding) map[string][]string {
+	out := make(map[string][]string)
+	for _, f := range found {
+		out[f.File] = append(out[f.File], f.Item)
 	}
-	sort.Ints(nums)
-	out := make([]string, len(nums))
-	for i, n := range nums {
-		out[i] = fmt.Sprintf("R%d", n)
+	return out
+}
+
+// Items returns the items of a rule's findings, in order.
+func (r *ValidationResult) Items(id string) []string {
+	return items(r.Findings[id])
+}
+
+// ItemsByFile returns the items of a rule's findings grouped by file.
+func (r *ValidationResult) ItemsByFile(id string) map[string][]string {
+	return itemsByFile(r.Findings[id])
+}
+
+// TraceMismatches returns the artifact-trace-mismatch findings, for fixing.
+// R89
+func (r *ValidationResult) TraceMismatches() []TraceMismatch {
+	var out []TraceMismatch
+	for _, f := range r.Findings["artifact-trace-mismatch"] {
+		design, side, _ := strings.Cut(f.Item, " (not in ")
+		out = append(out, TraceMismatch{CodeFile: f.File, DesignFile: design, MissingFrom: strings.TrimSuffix(side, ")")})
 	}
 	return out
 }
@@ -385,78 +1116,60 @@ func FormatRanges(ids []string) string {
 	return strings.Join(parts, ", ")
 }
 
-// HasIssues returns true if any issues were found.
+// HasIssues returns true if any enabled rule found something, whatever its
+// severity.
 func (r *ValidationResult) HasIssues() bool {
-	return len(r.UncoveredReqs) > 0 ||
-		len(r.MissingImplCoverage) > 0 ||
-		len(r.DuplicateReqs) > 0 ||
-		len(r.ReqNumberingGaps) > 0 ||
-		len(r.UnknownCRCRefs) > 0 ||
-		len(r.MissingArtifacts) > 0 ||
-		len(r.MissingTraceability) > 0 ||
-		len(r.MissingDesignRefs) > 0 ||
-		len(r.UnlistedDesignFiles) > 0 ||
-		len(r.MissingSpecSources) > 0 ||
-		len(r.MissingCRCSequences) > 0 ||
-		len(r.CheckboxedPermanent) > 0 ||
-		len(r.DuplicateGapIDs) > 0 ||
-		len(r.OrphanCRCNoReqField) > 0
-}
-
-// FormatText returns the issues-only text report. R84, R88
-func (r *ValidationResult) FormatText() string {
-	if !r.HasIssues() {
-		return "phase: validate OK\n"
+	reg := r.registry()
+	for _, rule := range reg.Rules() {
+		if reg.Enabled(rule.ID()) && rule.Report(r.Findings[rule.ID()]) != "" {
+			return true
+		}
 	}
+	return false
+}
 
-	var sb strings.Builder
-	sb.WriteString("issues:\n")
+// severityHeadings introduce each severity's block of the text report.
+var severityHeadings = map[Severity]string{SeverityError: "issues:", SeverityWarning: "warnings:", SeverityInfo: "info:"}
 
-	if s := FormatRanges(r.UncoveredReqs); s != "" {
-		fmt.Fprintf(&sb, "  uncovered requirements: %s\n", s)
-	}
-	if s := FormatRanges(r.MissingImplCoverage); s != "" {
-		fmt.Fprintf(&sb, "  missing impl coverage: %s\n", s)
-	}
-	if s := FormatRanges(r.DuplicateReqs); s != "" {
-		fmt.Fprintf(&sb, "  duplicate requirements: %s\n", s)
-	}
-	if s := FormatRanges(r.ReqNumberingGaps); s != "" {
-		fmt.Fprintf(&sb, "  numbering gaps: %s\n", s)
-	}
-	if len(r.UnknownCRCRefs) > 0 {
-		fmt.Fprintf(&sb, "  unknown CRC refs: %s\n", formatFileMap(r.UnknownCRCRefs, FormatRanges))
-	}
-	if len(r.MissingArtifacts) > 0 {
-		fmt.Fprintf(&sb, "  missing artifacts: %s\n", strings.Join(r.MissingArtifacts, ", "))
-	}
-	if len(r.MissingTraceability) > 0 {
-		fmt.Fprintf(&sb, "  missing traceability: %s\n", strings.Join(r.MissingTraceability, ", "))
-	}
-	if len(r.MissingDesignRefs) > 0 {
-		fmt.Fprintf(&sb, "  missing design refs: %s\n", formatFileMap(r.MissingDesignRefs, joinComma))
-	}
-	if len(r.UnlistedDesignFiles) > 0 {
-		fmt.Fprintf(&sb, "  unlisted design files: %s\n", strings.Join(r.UnlistedDesignFiles, ", "))
-	}
-	if len(r.MissingSpecSources) > 0 {
-		fmt.Fprintf(&sb, "  missing spec sources: %s\n", strings.Join(r.MissingSpecSources, ", "))
-	}
-	if len(r.MissingCRCSequences) > 0 {
-		fmt.Fprintf(&sb, "  CRC sequences not found: %s\n", formatFileMap(r.MissingCRCSequences, joinComma))
-	}
-	if len(r.OrphanCRCNoReqField) > 0 {
-		fmt.Fprintf(&sb, "  CRCs without Requirements field: %s\n", strings.Join(r.OrphanCRCNoReqField, ", "))
+// FormatIssues returns the findings as one block per severity (issues:,
+// warnings:, info:), one line per rule in registry order; coverage failures
+// end the issues: block. R84, R88, R137, R138
+func (r *ValidationResult) FormatIssues() string {
+	issues := r.registry().Issues(r)
+	var sb strings.Builder
+	for _, sev := range Severities {
+		var lines []string
+		for _, is := range issues {
+			if is.Severity == sev {
+				lines = append(lines, is.Text)
+			}
+		}
+		if sev == SeverityError {
+			lines = append(lines, r.CoverageFailures...)
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		sb.WriteString(severityHeadings[sev] + "\n")
+		for _, l := range lines {
+			fmt.Fprintf(&sb, "  %s\n", l)
+		}
 	}
-	if len(r.CheckboxedPermanent) > 0 {
-		fmt.Fprintf(&sb, "  permanent gaps with checkbox: %s\n", strings.Join(r.CheckboxedPermanent, ", "))
+	return sb.String()
+}
+
+// FormatText returns the issues-only text report followed by the status
+// line; on success with no findings, a single `phase: validate OK` line.
+func (r *ValidationResult) FormatText() string {
+	body := r.FormatIssues()
+	status := "OK"
+	if r.Failed() {
+		status = "FAILED"
 	}
-	if len(r.DuplicateGapIDs) > 0 {
-		fmt.Fprintf(&sb, "  duplicate gap IDs: %s\n", strings.Join(r.DuplicateGapIDs, ", "))
+	if body == "" {
+		return "phase: validate " + status + "\n"
 	}
-
-	sb.WriteString("\nphase: validate FAILED\n")
-	return sb.String()
+	return body + "\nphase: validate " + status + "\n"
 }
 
 // formatFileMap renders a map of file -> []ref entries, sorted by key, using
@@ -475,3 +1188,12 @@ func formatFileMap(m map[string][]string, render func([]string) string) string {
 }
 
 func joinComma(s []string) string { return strings.Join(s, ", ") }
+
+// joinQuoted quotes free-text entries such as test names, which may contain commas.
+func joinQuoted(s []string) string {
+	q := make([]string, len(s))
+	for i, v := range s {
+		q[i] = strconv.Quote(v)
+	}
+	return strings.Join(q, ", ")
+}